@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/galihaleanda/event-invitation/internal/config"
 	"github.com/galihaleanda/event-invitation/internal/infrastructure/cache"
@@ -15,6 +17,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	handler "github.com/galihaleanda/event-invitation/internal/handler/http"
+	"github.com/galihaleanda/event-invitation/internal/handler/web"
 )
 
 func main() {
@@ -35,16 +38,23 @@ func main() {
 	log.Println("✓ Connected to PostgreSQL")
 
 	// Connect Redis (optional, warn if not available)
-	_, err = cache.NewRedis(cfg)
+	var limiter cache.RateLimiter
+	var appCache cache.Cache
+	rdb, err := cache.NewRedis(cfg)
 	if err != nil {
 		log.Printf("⚠ Redis not available: %v", err)
+		limiter = cache.NewMemoryRateLimiter()
+		appCache = cache.NewMemoryCache()
 	} else {
 		log.Println("✓ Connected to Redis")
+		limiter = cache.NewRedisRateLimiter(rdb)
+		appCache = cache.NewRedisCache(rdb)
 	}
 
-	// Ensure storage directories
-	if err := storage.EnsureStorageDirs(cfg); err != nil {
-		log.Fatalf("failed to create storage dirs: %v", err)
+	// File storage (local disk or S3-compatible)
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("failed to init storage: %v", err)
 	}
 
 	// Repositories
@@ -53,19 +63,34 @@ func main() {
 	eventRepo := repository.NewEventRepository(db)
 	guestRepo := repository.NewGuestRepository(db)
 	mediaRepo := repository.NewMediaRepository(db)
+	collaboratorRepo := repository.NewCollaboratorRepository(db)
 
 	// Services
 	authSvc := service.NewAuthService(userRepo, cfg)
-	templateSvc := service.NewTemplateService(templateRepo)
-	eventSvc := service.NewEventService(eventRepo, templateRepo, mediaRepo)
-	rsvpSvc := service.NewRSVPService(guestRepo, eventRepo)
+	templateSvc := service.NewTemplateService(templateRepo, store, cfg)
+	authz := service.NewAuthorizer(eventRepo, collaboratorRepo)
+	eventSvc := service.NewEventService(eventRepo, templateRepo, mediaRepo, guestRepo, authz)
+	rsvpSvc := service.NewRSVPService(guestRepo, eventRepo, authz)
+	wishSvc := service.NewWishService(guestRepo, eventRepo, authz)
+	checkInSvc := service.NewCheckInService(guestRepo, authz, cfg)
+	collaboratorSvc := service.NewCollaboratorService(collaboratorRepo, userRepo, authz)
+	mediaProcessor := service.NewMediaProcessor(mediaRepo, store)
+	mediaSvc := service.NewMediaService(mediaRepo, eventRepo, guestRepo, authz, store, mediaProcessor, cfg)
+	previewSvc := service.NewPreviewService(eventRepo, mediaRepo, store, appCache)
+
+	// Background image processing (thumbnails, EXIF stripping)
+	go mediaProcessor.Run(context.Background())
 
 	// Handlers
 	authHandler := handler.NewAuthHandler(authSvc)
 	templateHandler := handler.NewTemplateHandler(templateSvc)
 	eventHandler := handler.NewEventHandler(eventSvc)
 	rsvpHandler := handler.NewRSVPHandler(rsvpSvc)
-	mediaHandler := handler.NewMediaHandler(mediaRepo, eventRepo, cfg)
+	wishHandler := handler.NewWishHandler(wishSvc)
+	checkInHandler := handler.NewCheckInHandler(checkInSvc)
+	collaboratorHandler := handler.NewCollaboratorHandler(collaboratorSvc)
+	mediaHandler := handler.NewMediaHandler(mediaSvc, cfg.Storage.MaxImageSize)
+	pageHandler := web.NewPageHandler(eventSvc, wishSvc, previewSvc, cfg.App.PublicURL)
 
 	// Gin setup
 	if cfg.App.Env == "production" {
@@ -73,12 +98,20 @@ func main() {
 	}
 
 	r := gin.New()
+	// Only listed proxies may set the client IP that rate limits key on
+	if err := r.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Fatalf("Invalid APP_TRUSTED_PROXIES: %v", err)
+	}
 	r.Use(middleware.Logger())
 	r.Use(middleware.CORS())
 	r.Use(gin.Recovery())
 
-	// Serve uploaded files
-	r.Static("/uploads", cfg.Storage.BasePath)
+	// Serve and accept direct uploads of local files (S3 objects are
+	// served by the bucket)
+	if local, ok := store.(*storage.Local); ok {
+		r.Static("/uploads", cfg.Storage.BasePath)
+		r.PUT("/uploads/*filepath", handler.NewUploadHandler(local, mediaRepo).Put)
+	}
 
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
@@ -99,15 +132,26 @@ func main() {
 		templates := v1.Group("/templates")
 		{
 			templates.GET("", templateHandler.GetAll)
+			templates.GET("/section-types", templateHandler.GetSectionSchemas)
 			templates.GET("/:id", templateHandler.GetByID)
 		}
 
 		// Public event page
 		v1.GET("/e/:slug", eventHandler.GetPublic)
+		v1.GET("/e/:slug/wishes", wishHandler.GetPublic)
+		v1.POST("/e/:slug/photos",
+			middleware.RateLimit(limiter, "guest-photos", cfg.RateLimit.GuestUploadsPerHour, time.Hour),
+			mediaHandler.GuestUpload,
+		)
 
 		// Public RSVP submission
 		v1.POST("/events/:id/rsvp", rsvpHandler.Submit)
 
+		// Guest edits their own RSVP (keyed by guest code)
+		v1.GET("/rsvp/:code", rsvpHandler.GetMyRSVP)
+		v1.PATCH("/rsvp/:code", rsvpHandler.UpdateMyRSVP)
+		v1.GET("/rsvp/:code/qr", checkInHandler.GetMyQR)
+
 		// Protected routes
 		protected := v1.Group("")
 		protected.Use(middleware.AuthMiddleware(cfg))
@@ -122,19 +166,98 @@ func main() {
 				events.DELETE("/:id", eventHandler.Delete)
 				events.PATCH("/:id/publish", eventHandler.Publish)
 				events.PUT("/:id/theme", eventHandler.UpdateTheme)
+				events.GET("/:id/music", eventHandler.GetMusic)
+				events.PUT("/:id/music", eventHandler.UpdateMusic)
+				events.DELETE("/:id/music", eventHandler.DeleteMusic)
 				events.PATCH("/:id/sections/:sectionId", eventHandler.UpdateSection)
+				events.GET("/:id/template-upgrade", eventHandler.GetTemplateUpgrade)
+				events.POST("/:id/template-upgrade", eventHandler.UpgradeTemplate)
+				events.GET("/:id/stats", eventHandler.GetStats)
+
+				// RSVP questions
+				events.GET("/:id/questions", eventHandler.GetQuestions)
+				events.POST("/:id/questions", eventHandler.CreateQuestion)
+				events.PATCH("/:id/questions/:questionId", eventHandler.UpdateQuestion)
+				events.DELETE("/:id/questions/:questionId", eventHandler.DeleteQuestion)
+
+				// Sessions (e.g. akad & resepsi)
+				events.GET("/:id/sessions", eventHandler.GetSessions)
+				events.POST("/:id/sessions", eventHandler.CreateSession)
+				events.PATCH("/:id/sessions/:sessionId", eventHandler.UpdateSession)
+				events.DELETE("/:id/sessions/:sessionId", eventHandler.DeleteSession)
 
 				// Guests (owner only)
 				events.GET("/:id/guests", rsvpHandler.GetGuests)
+				events.POST("/:id/guests", rsvpHandler.CreateGuest)
+				events.POST("/:id/guests/import", rsvpHandler.ImportGuests)
+				events.GET("/:id/guests/export", rsvpHandler.ExportGuests)
+				events.PATCH("/:id/guests/:guestId", rsvpHandler.UpdateGuest)
+				events.DELETE("/:id/guests/:guestId", rsvpHandler.DeleteGuest)
+				events.POST("/:id/guests/:guestId/promote", rsvpHandler.PromoteGuest)
+				events.GET("/:id/guests/:guestId/history", rsvpHandler.GetGuestHistory)
+				events.GET("/:id/guests/:guestId/qr", checkInHandler.GetGuestQR)
+
+				// Collaborators (co-organizers, ushers, viewers)
+				events.GET("/:id/collaborators", collaboratorHandler.List)
+				events.POST("/:id/collaborators", collaboratorHandler.Invite)
+				events.PATCH("/:id/collaborators/:collaboratorId", collaboratorHandler.UpdateRole)
+				events.DELETE("/:id/collaborators/:collaboratorId", collaboratorHandler.Remove)
+
+				// Door check-in (scan a guest QR code)
+				events.POST("/:id/checkin", checkInHandler.CheckIn)
+
+				// Wishes moderation
+				events.GET("/:id/wishes", wishHandler.GetByEvent)
+				events.PATCH("/:id/wishes/:guestId", wishHandler.Moderate)
+				events.DELETE("/:id/wishes/:guestId", wishHandler.Delete)
 
 				// Media
 				events.POST("/:id/media", mediaHandler.Upload)
+				events.POST("/:id/media/uploads", mediaHandler.CreateUpload)
+				events.POST("/:id/media/uploads/:uploadId/confirm", mediaHandler.ConfirmUpload)
 				events.GET("/:id/media", mediaHandler.GetByEvent)
+				events.PUT("/:id/media/order", mediaHandler.Reorder)
+				events.GET("/:id/media/:mediaId/download", mediaHandler.Download)
+				events.PATCH("/:id/media/:mediaId", mediaHandler.Update)
+				events.POST("/:id/media/:mediaId/approve", mediaHandler.Approve)
 				events.DELETE("/:id/media/:mediaId", mediaHandler.Delete)
+
+				// Gallery albums
+				events.GET("/:id/albums", mediaHandler.GetAlbums)
+				events.POST("/:id/albums", mediaHandler.CreateAlbum)
+				events.PATCH("/:id/albums/:albumId", mediaHandler.UpdateAlbum)
+				events.DELETE("/:id/albums/:albumId", mediaHandler.DeleteAlbum)
+			}
+
+			// Collaboration invitations for the signed-in user
+			protected.GET("/invitations", collaboratorHandler.MyInvitations)
+			protected.POST("/invitations/:id/accept", collaboratorHandler.Accept)
+			protected.DELETE("/invitations/:id", collaboratorHandler.Decline)
+
+			// Template catalog (admin only)
+			admin := protected.Group("/admin")
+			admin.Use(middleware.AdminOnly(userRepo))
+			{
+				admin.GET("/templates", templateHandler.AdminGetAll)
+				admin.POST("/templates", templateHandler.Create)
+				admin.PUT("/templates/order", templateHandler.Reorder)
+				admin.GET("/templates/:id", templateHandler.AdminGetByID)
+				admin.PATCH("/templates/:id", templateHandler.Update)
+				admin.POST("/templates/:id/thumbnail", templateHandler.UploadThumbnail)
+				admin.GET("/templates/:id/preview", templateHandler.Preview)
+				admin.POST("/templates/:id/sections", templateHandler.CreateSection)
+				admin.PUT("/templates/:id/sections/order", templateHandler.ReorderSections)
+				admin.PATCH("/templates/:id/sections/:sectionId", templateHandler.UpdateSection)
+				admin.DELETE("/templates/:id/sections/:sectionId", templateHandler.DeleteSection)
 			}
 		}
 	}
 
+	// Server-rendered invitation page. New top-level routes must be added
+	// to the reserved slugs in utils/slug.go.
+	r.GET("/:slug", pageHandler.Show)
+	r.GET("/:slug/preview.jpg", pageHandler.Preview)
+
 	addr := fmt.Sprintf(":%s", cfg.App.Port)
 	log.Printf("🚀 Server running on %s", addr)
 	if err := r.Run(addr); err != nil {
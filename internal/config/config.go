@@ -4,21 +4,30 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	Storage  StorageConfig
+	App       AppConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	CheckIn   CheckInConfig
+	Storage   StorageConfig
+	RateLimit RateLimitConfig
 }
 
 type AppConfig struct {
 	Env  string
 	Port string
+	// PublicURL is where invitation pages are served, used for absolute
+	// links in social preview metadata
+	PublicURL string
+	// TrustedProxies may set X-Forwarded-For; with none, the client IP is
+	// the peer address
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -42,9 +51,44 @@ type JWTConfig struct {
 	ExpiryHours int
 }
 
+// CheckInConfig holds the key that signs guest check-in QR codes.
+type CheckInConfig struct {
+	Secret string
+}
+
+// StorageConfig selects where uploads go. Driver is "local" (default) or
+// "s3" for any S3-compatible service such as MinIO.
 type StorageConfig struct {
+	Driver   string
 	BasePath string
 	BaseURL  string
+	// UploadSecret signs direct upload URLs for the local driver
+	UploadSecret string
+
+	// Maximum file size per media type and total storage per event, in
+	// bytes; a zero quota means unlimited
+	MaxImageSize int64
+	MaxVideoSize int64
+	MaxAudioSize int64
+	EventQuota   int64
+	// GuestQuota caps guest photos awaiting approval per event; they
+	// do not count against EventQuota until approved
+	GuestQuota int64
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+	// S3PublicURL overrides the public object URL base, e.g. a CDN
+	S3PublicURL string
+}
+
+// RateLimitConfig caps public endpoints per client IP.
+type RateLimitConfig struct {
+	// GuestUploadsPerHour limits photo uploads from the public page
+	GuestUploadsPerHour int
 }
 
 func (d DatabaseConfig) DSN() string {
@@ -60,11 +104,21 @@ func Load() (*Config, error) {
 
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
 	jwtExpiry, _ := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "72"))
+	jwtSecret := getEnv("JWT_SECRET", "change-me-in-production")
+	s3UseSSL, _ := strconv.ParseBool(getEnv("S3_USE_SSL", "true"))
+	maxImageMB, _ := strconv.ParseInt(getEnv("MAX_IMAGE_SIZE_MB", "10"), 10, 64)
+	maxVideoMB, _ := strconv.ParseInt(getEnv("MAX_VIDEO_SIZE_MB", "200"), 10, 64)
+	maxAudioMB, _ := strconv.ParseInt(getEnv("MAX_AUDIO_SIZE_MB", "20"), 10, 64)
+	eventQuotaMB, _ := strconv.ParseInt(getEnv("EVENT_STORAGE_QUOTA_MB", "1024"), 10, 64)
+	guestUploadQuotaMB, _ := strconv.ParseInt(getEnv("GUEST_UPLOAD_QUOTA_MB", "200"), 10, 64)
+	guestUploadsPerHour, _ := strconv.Atoi(getEnv("GUEST_UPLOADS_PER_HOUR", "20"))
 
 	cfg := &Config{
 		App: AppConfig{
-			Env:  getEnv("APP_ENV", "development"),
-			Port: getEnv("APP_PORT", "8080"),
+			Env:            getEnv("APP_ENV", "development"),
+			Port:           getEnv("APP_PORT", "8080"),
+			PublicURL:      strings.TrimRight(getEnv("APP_PUBLIC_URL", "http://localhost:8080"), "/"),
+			TrustedProxies: splitList(getEnv("APP_TRUSTED_PROXIES", "")),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -81,18 +135,49 @@ func Load() (*Config, error) {
 			DB:       redisDB,
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "change-me-in-production"),
+			Secret:      jwtSecret,
 			ExpiryHours: jwtExpiry,
 		},
+		CheckIn: CheckInConfig{
+			Secret: getEnv("CHECKIN_SECRET", jwtSecret),
+		},
 		Storage: StorageConfig{
-			BasePath: getEnv("STORAGE_BASE_PATH", "./uploads"),
-			BaseURL:  getEnv("STORAGE_BASE_URL", "http://localhost:8080/uploads"),
+			Driver:       getEnv("STORAGE_DRIVER", "local"),
+			BasePath:     getEnv("STORAGE_BASE_PATH", "./uploads"),
+			BaseURL:      getEnv("STORAGE_BASE_URL", "http://localhost:8080/uploads"),
+			UploadSecret: getEnv("STORAGE_UPLOAD_SECRET", jwtSecret),
+			MaxImageSize: maxImageMB << 20,
+			MaxVideoSize: maxVideoMB << 20,
+			MaxAudioSize: maxAudioMB << 20,
+			EventQuota:   eventQuotaMB << 20,
+			GuestQuota:   guestUploadQuotaMB << 20,
+			S3Endpoint:   getEnv("S3_ENDPOINT", ""),
+			S3Region:     getEnv("S3_REGION", ""),
+			S3Bucket:     getEnv("S3_BUCKET", ""),
+			S3AccessKey:  getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:  getEnv("S3_SECRET_KEY", ""),
+			S3UseSSL:     s3UseSSL,
+			S3PublicURL:  getEnv("S3_PUBLIC_URL", ""),
+		},
+		RateLimit: RateLimitConfig{
+			GuestUploadsPerHour: guestUploadsPerHour,
 		},
 	}
 
 	return cfg, nil
 }
 
+// splitList parses a comma-separated value, skipping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
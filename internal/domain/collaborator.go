@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CollaboratorRole is what a user may do on an event. The event creator
+// (Event.UserID) is always an owner without a collaborator record.
+type CollaboratorRole string
+
+const (
+	CollaboratorRoleOwner  CollaboratorRole = "owner"
+	CollaboratorRoleEditor CollaboratorRole = "editor"
+	// CollaboratorRoleUsher can only look up guests and check them in
+	CollaboratorRoleUsher  CollaboratorRole = "usher"
+	CollaboratorRoleViewer CollaboratorRole = "viewer"
+)
+
+// EventCollaborator is an invitation to help run an event. It is pending
+// until the user with the invited email accepts it.
+type EventCollaborator struct {
+	ID         uuid.UUID        `db:"id" json:"id"`
+	EventID    uuid.UUID        `db:"event_id" json:"event_id"`
+	Email      string           `db:"email" json:"email"`
+	UserID     *uuid.UUID       `db:"user_id" json:"user_id"`
+	Role       CollaboratorRole `db:"role" json:"role"`
+	InvitedBy  uuid.UUID        `db:"invited_by" json:"invited_by"`
+	AcceptedAt *time.Time       `db:"accepted_at" json:"accepted_at"`
+	CreatedAt  time.Time        `db:"created_at" json:"created_at"`
+
+	// Populated when listing a user's pending invitations
+	EventTitle string `db:"event_title" json:"event_title,omitempty"`
+}
+
+// InviteCollaboratorRequest cannot grant "owner": an event has exactly one
+// owner, its creator.
+type InviteCollaboratorRequest struct {
+	Email string           `json:"email" binding:"required,email"`
+	Role  CollaboratorRole `json:"role" binding:"required,oneof=editor usher viewer"`
+}
+
+type UpdateCollaboratorRequest struct {
+	Role CollaboratorRole `json:"role" binding:"required,oneof=editor usher viewer"`
+}
+
+type CollaboratorRepository interface {
+	Create(ctx context.Context, collaborator *EventCollaborator) error
+	FindByID(ctx context.Context, id uuid.UUID) (*EventCollaborator, error)
+	FindByEventID(ctx context.Context, eventID uuid.UUID) ([]EventCollaborator, error)
+	// FindMember returns the accepted collaborator record of a user, or
+	// nil if the user is not a member of the event.
+	FindMember(ctx context.Context, eventID, userID uuid.UUID) (*EventCollaborator, error)
+	FindPendingByEmail(ctx context.Context, email string) ([]EventCollaborator, error)
+	EmailExists(ctx context.Context, eventID uuid.UUID, email string) (bool, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, role CollaboratorRole) error
+	Accept(ctx context.Context, id, userID uuid.UUID, at time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -6,21 +6,43 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// GuestUploadMode says who may upload photos from the public page.
+type GuestUploadMode string
+
+const (
+	GuestUploadsOff    GuestUploadMode = "off"
+	GuestUploadsOpen   GuestUploadMode = "open"   // any visitor
+	GuestUploadsGuests GuestUploadMode = "guests" // requires a guest code
 )
 
 type Event struct {
 	ID              uuid.UUID  `db:"id" json:"id"`
 	UserID          uuid.UUID  `db:"user_id" json:"user_id"`
 	TemplateID      uuid.UUID  `db:"template_id" json:"template_id"`
+	TemplateVersion int        `db:"template_version" json:"template_version"` // version the sections came from
 	Title           string     `db:"title" json:"title"`
 	Slug            string     `db:"slug" json:"slug"`
 	EventDate       time.Time  `db:"event_date" json:"event_date"`
 	LocationName    *string    `db:"location_name" json:"location_name"`
 	LocationAddress *string    `db:"location_address" json:"location_address"`
-	IsPublished     bool       `db:"is_published" json:"is_published"`
-	ViewCount       int        `db:"view_count" json:"view_count"`
-	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+	MaxPartySize    int        `db:"max_party_size" json:"max_party_size"`
+	RSVPDeadline    *time.Time `db:"rsvp_deadline" json:"rsvp_deadline"`
+	MaxAttendees    *int       `db:"max_attendees" json:"max_attendees"`
+	WaitlistEnabled bool       `db:"waitlist_enabled" json:"waitlist_enabled"`
+	// WishesAutoApprove publishes guest messages without owner review
+	WishesAutoApprove bool `db:"wishes_auto_approve" json:"wishes_auto_approve"`
+	// GuestUploads controls photo uploads from the public page
+	GuestUploads GuestUploadMode `db:"guest_uploads" json:"guest_uploads"`
+	IsPublished  bool            `db:"is_published" json:"is_published"`
+	ViewCount    int             `db:"view_count" json:"view_count"`
+	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time       `db:"updated_at" json:"updated_at"`
+
+	// Role of the requesting user, set when listing their events
+	Role CollaboratorRole `db:"role" json:"role,omitempty"`
 
 	// Relations (populated on demand)
 	Theme    *EventTheme    `db:"-" json:"theme,omitempty"`
@@ -38,13 +60,31 @@ type EventTheme struct {
 	CreatedAt      time.Time `db:"created_at" json:"created_at"`
 }
 
+// EventMusic is the background track of the invitation page, chosen from
+// the event's audio media.
+type EventMusic struct {
+	EventID      uuid.UUID `db:"event_id" json:"event_id"`
+	MediaID      uuid.UUID `db:"media_id" json:"media_id"`
+	Autoplay     bool      `db:"autoplay" json:"autoplay"`
+	Loop         bool      `db:"loop" json:"loop"`
+	StartSeconds int       `db:"start_seconds" json:"start_seconds"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	// URL of the audio file, filled in when read
+	URL string `db:"-" json:"url"`
+}
+
 type EventSection struct {
 	ID                uuid.UUID       `db:"id" json:"id"`
 	EventID           uuid.UUID       `db:"event_id" json:"event_id"`
 	TemplateSectionID uuid.UUID       `db:"template_section_id" json:"template_section_id"`
+	Type              string          `db:"type" json:"type"`
 	Content           json.RawMessage `db:"content" json:"content"`
 	IsVisible         bool            `db:"is_visible" json:"is_visible"`
 	SortOrder         int             `db:"sort_order" json:"sort_order"`
+	// Restricts the section to these guest groups / guest IDs; both empty
+	// means every visitor sees it
+	VisibleGroups   pq.StringArray `db:"visible_groups" json:"visible_groups,omitempty"`
+	VisibleGuestIDs pq.StringArray `db:"visible_guest_ids" json:"visible_guest_ids,omitempty"`
 }
 
 // Request / Response types
@@ -54,6 +94,7 @@ type CreateEventRequest struct {
 	EventDate       string  `json:"event_date" binding:"required"`
 	LocationName    *string `json:"location_name"`
 	LocationAddress *string `json:"location_address"`
+	MaxPartySize    *int    `json:"max_party_size" binding:"omitempty,min=1,max=50"`
 }
 
 type UpdateEventRequest struct {
@@ -61,6 +102,15 @@ type UpdateEventRequest struct {
 	EventDate       *string `json:"event_date"`
 	LocationName    *string `json:"location_name"`
 	LocationAddress *string `json:"location_address"`
+	MaxPartySize    *int    `json:"max_party_size" binding:"omitempty,min=1,max=50"`
+	// RSVPDeadline is RFC3339; an empty string removes the deadline.
+	RSVPDeadline *string `json:"rsvp_deadline"`
+	// MaxAttendees caps the confirmed headcount; 0 removes the limit.
+	MaxAttendees      *int  `json:"max_attendees" binding:"omitempty,min=0"`
+	WaitlistEnabled   *bool `json:"waitlist_enabled"`
+	WishesAutoApprove *bool `json:"wishes_auto_approve"`
+	// GuestUploads is off, open or guests
+	GuestUploads *string `json:"guest_uploads" binding:"omitempty,oneof=off open guests"`
 }
 
 type UpdateThemeRequest struct {
@@ -71,26 +121,73 @@ type UpdateThemeRequest struct {
 	CustomCSS      *string `json:"custom_css"`
 }
 
+type UpdateMusicRequest struct {
+	MediaID string `json:"media_id" binding:"required"`
+	// Autoplay and Loop default to true
+	Autoplay     *bool `json:"autoplay"`
+	Loop         *bool `json:"loop"`
+	StartSeconds int   `json:"start_seconds" binding:"min=0,max=3600"`
+}
+
 type UpdateSectionRequest struct {
 	Content   json.RawMessage `json:"content"`
 	IsVisible *bool           `json:"is_visible"`
 	SortOrder *int            `json:"sort_order"`
+	// Audience; nil keeps it, empty lists make the section public again
+	VisibleGroups   []string `json:"visible_groups"`
+	VisibleGuestIDs []string `json:"visible_guest_ids"`
 }
 
 type PublicEventResponse struct {
-	Event    *Event         `json:"event"`
-	Theme    *EventTheme    `json:"theme"`
-	Sections []EventSection `json:"sections"`
-	Gallery  []Media        `json:"gallery"`
-	Stats    *EventStats    `json:"stats"`
+	Event     *Event          `json:"event"`
+	Theme     *EventTheme     `json:"theme"`
+	Sections  []EventSection  `json:"sections"`
+	Gallery   []Media         `json:"gallery"`
+	Albums    []MediaAlbum    `json:"albums"`
+	Cover     *Media          `json:"cover,omitempty"`
+	Music     *EventMusic     `json:"music,omitempty"`
+	Stats     *EventStats     `json:"stats"`
+	Questions []EventQuestion `json:"questions"`
+	Sessions  []EventSession  `json:"sessions"`
+	Guest     *Guest          `json:"guest,omitempty"`
+}
+
+// EventPreview is the social share image of an event. Version changes
+// whenever the image would look different.
+type EventPreview struct {
+	Image   []byte
+	Version string
+}
+
+// TemplateUpgrade brings an event's sections up to the latest version of
+// its template. Content the owner edited is never overwritten.
+type TemplateUpgrade struct {
+	FromVersion int `json:"from_version"`
+	ToVersion   int `json:"to_version"`
+	// Added are template sections the event does not have yet
+	Added []EventSection `json:"added"`
+	// Updated are sections whose unedited fields take the new defaults
+	Updated []EventSection `json:"updated"`
+	// Original holds the content each updated section was planned from
+	Original map[uuid.UUID]json.RawMessage `json:"-"`
 }
 
 type EventStats struct {
-	TotalRSVP      int `json:"total_rsvp"`
-	TotalAttending int `json:"total_attending"`
-	TotalDeclined  int `json:"total_declined"`
-	TotalPending   int `json:"total_pending"`
-	TotalMessages  int `json:"total_messages"`
+	TotalRSVP      int `db:"total_rsvp" json:"total_rsvp"`
+	TotalAttending int `db:"total_attending" json:"total_attending"`
+	TotalHeadcount int `db:"total_headcount" json:"total_headcount"`
+	TotalDeclined  int `db:"total_declined" json:"total_declined"`
+	TotalPending   int `db:"total_pending" json:"total_pending"`
+	TotalWaitlist  int `db:"total_waitlist" json:"total_waitlist"`
+	TotalMessages  int `db:"total_messages" json:"total_messages"`
+	// Door check-in: guests scanned and the headcount they RSVP'd with
+	TotalCheckedIn     int `db:"total_checked_in" json:"total_checked_in"`
+	CheckedInHeadcount int `db:"checked_in_headcount" json:"checked_in_headcount"`
+
+	// Answers to custom RSVP questions (owner view only)
+	Answers []QuestionStats `db:"-" json:"answers,omitempty"`
+	// Attendance per session of a multi-session event (owner view only)
+	Sessions []SessionStats `db:"-" json:"sessions,omitempty"`
 }
 
 type EventRepository interface {
@@ -98,6 +195,9 @@ type EventRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*Event, error)
 	FindBySlug(ctx context.Context, slug string) (*Event, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]Event, error)
+	// FindByMember returns events the user owns or collaborates on, with
+	// Event.Role set.
+	FindByMember(ctx context.Context, userID uuid.UUID) ([]Event, error)
 	Update(ctx context.Context, event *Event) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	IncrementViewCount(ctx context.Context, id uuid.UUID) error
@@ -107,11 +207,33 @@ type EventRepository interface {
 	UpsertTheme(ctx context.Context, theme *EventTheme) error
 	FindThemeByEventID(ctx context.Context, eventID uuid.UUID) (*EventTheme, error)
 
+	// Background music
+	UpsertMusic(ctx context.Context, music *EventMusic) error
+	FindMusicByEventID(ctx context.Context, eventID uuid.UUID) (*EventMusic, error)
+	DeleteMusic(ctx context.Context, eventID uuid.UUID) error
+
 	// Sections
 	CreateSections(ctx context.Context, sections []EventSection) error
 	FindSectionsByEventID(ctx context.Context, eventID uuid.UUID) ([]EventSection, error)
 	UpdateSection(ctx context.Context, section *EventSection) error
+	// ApplyTemplateUpgrade saves an upgrade in one transaction. It returns
+	// false, changing nothing, when the event is no longer on FromVersion or
+	// an updated section was edited after the upgrade was planned.
+	ApplyTemplateUpgrade(ctx context.Context, eventID uuid.UUID, upgrade *TemplateUpgrade) (bool, error)
+
+	// RSVP questions
+	CreateQuestion(ctx context.Context, question *EventQuestion) error
+	FindQuestionsByEventID(ctx context.Context, eventID uuid.UUID) ([]EventQuestion, error)
+	UpdateQuestion(ctx context.Context, question *EventQuestion) error
+	DeleteQuestion(ctx context.Context, eventID, questionID uuid.UUID) error
+
+	// Sessions
+	CreateSession(ctx context.Context, session *EventSession) error
+	FindSessionsByEventID(ctx context.Context, eventID uuid.UUID) ([]EventSession, error)
+	UpdateSession(ctx context.Context, session *EventSession) error
+	DeleteSession(ctx context.Context, eventID, sessionID uuid.UUID) error
 
 	// Stats
 	GetStats(ctx context.Context, eventID uuid.UUID) (*EventStats, error)
+	GetSessionStats(ctx context.Context, eventID uuid.UUID) ([]SessionStats, error)
 }
@@ -2,9 +2,11 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type RSVPStatus string
@@ -13,6 +15,17 @@ const (
 	RSVPStatusPending RSVPStatus = "pending"
 	RSVPStatusYes     RSVPStatus = "yes"
 	RSVPStatusNo      RSVPStatus = "no"
+	// RSVPStatusWaitlist is assigned by the server when a "yes" arrives
+	// after the event is full; owners promote these guests manually.
+	RSVPStatusWaitlist RSVPStatus = "waitlist"
+)
+
+type MessageStatus string
+
+const (
+	MessageStatusPending  MessageStatus = "pending"
+	MessageStatusApproved MessageStatus = "approved"
+	MessageStatusHidden   MessageStatus = "hidden"
 )
 
 type Guest struct {
@@ -23,7 +36,18 @@ type Guest struct {
 	Message    *string    `db:"message" json:"message"`
 	RSVPStatus RSVPStatus `db:"rsvp_status" json:"rsvp_status"`
 	GuestCode  *string    `db:"guest_code" json:"guest_code"`
-	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	GuestGroup *string    `db:"guest_group" json:"guest_group"`
+	PartySize  int        `db:"party_size" json:"party_size"`
+	Attendees  int        `db:"attendees" json:"attendees"`
+	// Answers to the event's custom questions, keyed by question ID
+	Answers json.RawMessage `db:"answers" json:"answers"`
+	// IDs of the event sessions the guest attends
+	SessionIDs pq.StringArray `db:"session_ids" json:"session_ids"`
+	// Wishes wall moderation of Message
+	MessageStatus MessageStatus `db:"message_status" json:"message_status"`
+	MessagePinned bool          `db:"message_pinned" json:"message_pinned"`
+	CheckedInAt   *time.Time    `db:"checked_in_at" json:"checked_in_at"`
+	CreatedAt     time.Time     `db:"created_at" json:"created_at"`
 }
 
 type RSVPRequest struct {
@@ -31,12 +55,158 @@ type RSVPRequest struct {
 	Phone     *string    `json:"phone"`
 	Message   *string    `json:"message"`
 	Status    RSVPStatus `json:"status" binding:"required,oneof=yes no pending"`
+	Attendees *int       `json:"attendees" binding:"omitempty,min=1,max=50"`
 	GuestCode *string    `json:"guest_code"`
+	// Answers maps question ID to a string, []string or number
+	Answers map[string]interface{} `json:"answers"`
+	// SessionIDs picks sessions of a multi-session event; defaults to all
+	SessionIDs []string `json:"session_ids"`
+}
+
+// UpdateRSVPRequest lets a guest change or cancel (status "no") their own
+// answer using their guest code.
+type UpdateRSVPRequest struct {
+	Status    *RSVPStatus            `json:"status" binding:"omitempty,oneof=yes no pending"`
+	Attendees *int                   `json:"attendees" binding:"omitempty,min=1,max=50"`
+	Message   *string                `json:"message"`
+	Phone     *string                `json:"phone"`
+	Answers   map[string]interface{} `json:"answers"`
+	// SessionIDs replaces the chosen sessions; nil keeps them
+	SessionIDs []string `json:"session_ids"`
+}
+
+type RSVPChangeSource string
+
+const (
+	RSVPChangeSourceGuest RSVPChangeSource = "guest"
+	RSVPChangeSourceOwner RSVPChangeSource = "owner"
+)
+
+// GuestRSVPChange records one change of a guest's answer, e.g. yes -> no.
+type GuestRSVPChange struct {
+	ID            uuid.UUID        `db:"id" json:"id"`
+	GuestID       uuid.UUID        `db:"guest_id" json:"guest_id"`
+	FromStatus    RSVPStatus       `db:"from_status" json:"from_status"`
+	ToStatus      RSVPStatus       `db:"to_status" json:"to_status"`
+	FromAttendees int              `db:"from_attendees" json:"from_attendees"`
+	ToAttendees   int              `db:"to_attendees" json:"to_attendees"`
+	Source        RSVPChangeSource `db:"source" json:"source"`
+	CreatedAt     time.Time        `db:"created_at" json:"created_at"`
+}
+
+// Wish is the public view of an approved guest message.
+type Wish struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	Message   string    `db:"message" json:"message"`
+	IsPinned  bool      `db:"message_pinned" json:"is_pinned"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type WishPage struct {
+	Items []Wish `json:"items"`
+	Page  int    `json:"page"`
+	Limit int    `json:"limit"`
+	Total int    `json:"total"`
+}
+
+type ModerateWishRequest struct {
+	Action string `json:"action" binding:"required,oneof=approve hide pin unpin"`
+}
+
+type CheckInRequest struct {
+	Token string `json:"token" binding:"required"`
+	// Override checks in a guest who declined or is on the waitlist
+	Override bool `json:"override"`
+}
+
+// CheckInResult is returned for every scan. A re-scan of a guest who is
+// already in reports AlreadyCheckedIn with the original time instead of
+// checking them in again.
+type CheckInResult struct {
+	Guest            *Guest     `json:"guest"`
+	RSVPStatus       RSVPStatus `json:"rsvp_status"`
+	AlreadyCheckedIn bool       `json:"already_checked_in"`
+	CheckedInAt      time.Time  `json:"checked_in_at"`
+}
+
+// CreateGuestRequest pre-registers an invitee before they RSVP.
+type CreateGuestRequest struct {
+	Name       string  `json:"name" binding:"required,min=2,max=150"`
+	Phone      *string `json:"phone"`
+	PartySize  *int    `json:"party_size" binding:"omitempty,min=1,max=50"`
+	GuestGroup *string `json:"guest_group" binding:"omitempty,max=50"`
+}
+
+type UpdateGuestRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=2,max=150"`
+	Phone     *string `json:"phone"`
+	PartySize *int    `json:"party_size" binding:"omitempty,min=1,max=50"`
+	// GuestGroup controls restricted sections; an empty string removes it
+	GuestGroup *string `json:"guest_group" binding:"omitempty,max=50"`
+}
+
+// ImportGuestRowError points at a spreadsheet row (1-based, header included)
+// so owners can fix the file and re-upload.
+type ImportGuestRowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+type ImportGuestsResult struct {
+	TotalRows int                   `json:"total_rows"`
+	Imported  int                   `json:"imported"`
+	Errors    []ImportGuestRowError `json:"errors"`
+}
+
+// GuestExport is the tabular guest list used for CSV/XLSX/PDF downloads.
+// Summary holds question / answer / count rows for custom RSVP questions.
+type GuestExport struct {
+	Event   *Event
+	Header  []string
+	Rows    [][]string
+	Summary [][]string
+}
+
+// RSVPWrite is a guest's answer as saved by GuestRepository.SaveRSVP.
+type RSVPWrite struct {
+	// Guest carries the new status, attendees, phone, message, answers,
+	// sessions and message status; the whole record is inserted when Create
+	// is set
+	Guest  *Guest
+	Create bool
+	// Decide, when set, picks the final status from the event's confirmed
+	// headcount, not counting the guest's own seats
+	Decide func(headcount int) (RSVPStatus, error)
+	// Change, when set, is logged if the final status or attendees differ
+	// from its From values; its To values are filled in on save
+	Change *GuestRSVPChange
 }
 
 type GuestRepository interface {
 	Create(ctx context.Context, guest *Guest) error
+	CreateBatch(ctx context.Context, guests []Guest) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Guest, error)
 	FindByEventID(ctx context.Context, eventID uuid.UUID) ([]Guest, error)
 	FindByGuestCode(ctx context.Context, code string) (*Guest, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status RSVPStatus) error
+	GuestCodeExists(ctx context.Context, code string) (bool, error)
+	Update(ctx context.Context, guest *Guest) error
+	// SaveRSVP stores an answer in one transaction holding the event row
+	// lock, so concurrent RSVPs cannot overbook the event.
+	SaveRSVP(ctx context.Context, w *RSVPWrite) error
+	// MarkCheckedIn sets checked_in_at unless already set and reports
+	// whether this call did it.
+	MarkCheckedIn(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Wishes
+	FindApprovedWishes(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]Wish, error)
+	CountApprovedWishes(ctx context.Context, eventID uuid.UUID) (int, error)
+	FindMessagesByEventID(ctx context.Context, eventID uuid.UUID, status MessageStatus) ([]Guest, error)
+	UpdateMessageStatus(ctx context.Context, id uuid.UUID, status MessageStatus) error
+	UpdateMessagePinned(ctx context.Context, id uuid.UUID, pinned bool) error
+	ClearMessage(ctx context.Context, id uuid.UUID) error
+
+	// RSVP history
+	FindChangesByGuestID(ctx context.Context, guestID uuid.UUID) ([]GuestRSVPChange, error)
 }
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,16 +16,160 @@ const (
 	MediaTypeAudio MediaType = "audio"
 )
 
+// Approval status of a media file. Photos uploaded by guests stay pending
+// until the owner approves them.
+const (
+	MediaPending  = "pending"
+	MediaApproved = "approved"
+)
+
+// Processing status of a media file. Photos start as pending until the
+// image pipeline has stripped their metadata and rendered the variants.
+const (
+	MediaStatusPending = "pending"
+	MediaStatusReady   = "ready"
+	MediaStatusFailed  = "failed"
+)
+
 type Media struct {
 	ID        uuid.UUID `db:"id" json:"id"`
 	EventID   uuid.UUID `db:"event_id" json:"event_id"`
 	FileURL   string    `db:"file_url" json:"file_url"`
 	MediaType MediaType `db:"media_type" json:"media_type"`
+	// StorageKey locates the file in the storage backend
+	StorageKey *string `db:"storage_key" json:"-"`
+	SizeBytes  int64   `db:"size_bytes" json:"size_bytes"`
+	// Upright pixel size of images, set once processed
+	Width  *int `db:"width" json:"width,omitempty"`
+	Height *int `db:"height" json:"height,omitempty"`
+	// Resized copies of an image as a JSON array of MediaVariant
+	Variants         json.RawMessage `db:"variants" json:"variants,omitempty"`
+	ProcessingStatus string          `db:"processing_status" json:"processing_status"`
+	// Gallery presentation; the public gallery is ordered by SortOrder
+	Caption   *string    `db:"caption" json:"caption"`
+	AltText   *string    `db:"alt_text" json:"alt_text"`
+	SortOrder int        `db:"sort_order" json:"sort_order"`
+	IsCover   bool       `db:"is_cover" json:"is_cover"`
+	AlbumID   *uuid.UUID `db:"album_id" json:"album_id"`
+	// Set for photos shared by guests from the public page
+	ApprovalStatus string     `db:"approval_status" json:"approval_status"`
+	GuestID        *uuid.UUID `db:"guest_id" json:"guest_id,omitempty"`
+	UploaderName   *string    `db:"uploader_name" json:"uploader_name,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+}
+
+// GuestUploadRequest holds the form fields sent with a guest's photo.
+type GuestUploadRequest struct {
+	// GuestCode identifies an invited guest; required when the event only
+	// accepts photos from guests
+	GuestCode string `form:"guest_code"`
+	Name      string `form:"name" binding:"max=100"`
+	Caption   string `form:"caption" binding:"max=500"`
+}
+
+type UpdateMediaRequest struct {
+	Caption *string `json:"caption" binding:"omitempty,max=500"`
+	AltText *string `json:"alt_text" binding:"omitempty,max=300"`
+	// AlbumID moves the media into an album; an empty string removes it
+	AlbumID *string `json:"album_id"`
+	// IsCover makes this the event's cover photo, replacing the previous one
+	IsCover *bool `json:"is_cover"`
+}
+
+// ReorderMediaRequest lists media in their new gallery order. Media left
+// out keep their relative order after the listed ones.
+type ReorderMediaRequest struct {
+	MediaIDs []string `json:"media_ids" binding:"required,min=1"`
+}
+
+// MediaAlbum groups gallery media, e.g. "Prewedding" and "Lamaran".
+type MediaAlbum struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	EventID   uuid.UUID `db:"event_id" json:"event_id"`
+	Title     string    `db:"title" json:"title"`
+	SortOrder int       `db:"sort_order" json:"sort_order"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
 
+type CreateAlbumRequest struct {
+	Title     string `json:"title" binding:"required,min=1,max=100"`
+	SortOrder int    `json:"sort_order"`
+}
+
+type UpdateAlbumRequest struct {
+	Title     *string `json:"title" binding:"omitempty,min=1,max=100"`
+	SortOrder *int    `json:"sort_order"`
+}
+
+// MediaVariant is a resized JPEG copy of an image, e.g. a thumbnail.
+type MediaVariant struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	URL    string `json:"url"`
+	Key    string `json:"key"`
+}
+
+// MediaUpload is a slot for a direct-to-storage upload. The client PUTs
+// the file to UploadURL and then confirms the slot, which turns it into a
+// Media once the stored object matches the declared size and type.
+type MediaUpload struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	EventID     uuid.UUID `db:"event_id" json:"event_id"`
+	StorageKey  string    `db:"storage_key" json:"-"`
+	MediaType   MediaType `db:"media_type" json:"media_type"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	SizeBytes   int64     `db:"size_bytes" json:"size_bytes"`
+	CreatedBy   uuid.UUID `db:"created_by" json:"-"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	// UploadURL is only returned when the slot is created, together with
+	// the headers the PUT must send
+	UploadURL     string            `db:"-" json:"upload_url,omitempty"`
+	UploadHeaders map[string]string `db:"-" json:"upload_headers,omitempty"`
+}
+
+type CreateMediaUploadRequest struct {
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
+
 type MediaRepository interface {
 	Create(ctx context.Context, media *Media) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Media, error)
 	FindByEventID(ctx context.Context, eventID uuid.UUID) ([]Media, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Update saves caption, alt text and album; the cover is set with
+	// SetCover.
+	Update(ctx context.Context, media *Media) error
+	// SetCover makes mediaID the event's only cover, or clears the cover
+	// when mediaID is nil.
+	SetCover(ctx context.Context, eventID uuid.UUID, mediaID *uuid.UUID) error
+	// Reorder gives the listed media sort orders 0..n-1 and moves the rest
+	// after them.
+	Reorder(ctx context.Context, eventID uuid.UUID, mediaIDs []uuid.UUID) error
+	UpdateApprovalStatus(ctx context.Context, id uuid.UUID, status string) error
+	// StorageUsedByEvent sums the event's files and open upload slots,
+	// leaving out guest photos awaiting approval.
+	StorageUsedByEvent(ctx context.Context, eventID uuid.UUID) (int64, error)
+	// PendingStorageByEvent sums guest photos awaiting approval.
+	PendingStorageByEvent(ctx context.Context, eventID uuid.UUID) (int64, error)
+
+	// Image processing
+	FindPendingIDs(ctx context.Context) ([]uuid.UUID, error)
+	// UpdateProcessing stores the processing result; false means the
+	// media was deleted in the meantime.
+	UpdateProcessing(ctx context.Context, media *Media) (bool, error)
+
+	// Direct upload slots
+	CreateUpload(ctx context.Context, upload *MediaUpload) error
+	FindUploadByID(ctx context.Context, id uuid.UUID) (*MediaUpload, error)
+	FindUploadByKey(ctx context.Context, key string) (*MediaUpload, error)
+	DeleteUpload(ctx context.Context, id uuid.UUID) error
+
+	// Albums
+	CreateAlbum(ctx context.Context, album *MediaAlbum) error
+	FindAlbumsByEventID(ctx context.Context, eventID uuid.UUID) ([]MediaAlbum, error)
+	UpdateAlbum(ctx context.Context, album *MediaAlbum) error
+	DeleteAlbum(ctx context.Context, eventID, albumID uuid.UUID) error
 }
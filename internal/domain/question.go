@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type QuestionType string
+
+const (
+	QuestionTypeText         QuestionType = "text"
+	QuestionTypeSingleChoice QuestionType = "single_choice"
+	QuestionTypeMultiChoice  QuestionType = "multi_choice"
+	QuestionTypeNumber       QuestionType = "number"
+)
+
+// EventQuestion is a custom RSVP form field, e.g. "meal preference".
+// Guest answers are stored in Guest.Answers keyed by question ID.
+type EventQuestion struct {
+	ID         uuid.UUID      `db:"id" json:"id"`
+	EventID    uuid.UUID      `db:"event_id" json:"event_id"`
+	Label      string         `db:"label" json:"label"`
+	Type       QuestionType   `db:"type" json:"type"`
+	Options    pq.StringArray `db:"options" json:"options"`
+	IsRequired bool           `db:"is_required" json:"is_required"`
+	SortOrder  int            `db:"sort_order" json:"sort_order"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+}
+
+type CreateQuestionRequest struct {
+	Label      string       `json:"label" binding:"required,min=1,max=200"`
+	Type       QuestionType `json:"type" binding:"required,oneof=text single_choice multi_choice number"`
+	Options    []string     `json:"options"`
+	IsRequired bool         `json:"is_required"`
+	SortOrder  int          `json:"sort_order"`
+}
+
+type UpdateQuestionRequest struct {
+	Label      *string  `json:"label" binding:"omitempty,min=1,max=200"`
+	Options    []string `json:"options"`
+	IsRequired *bool    `json:"is_required"`
+	SortOrder  *int     `json:"sort_order"`
+}
+
+// QuestionStats aggregates the answers of attending guests to one question.
+// Counts is filled for choice questions, Sum for number questions.
+type QuestionStats struct {
+	QuestionID uuid.UUID      `json:"question_id"`
+	Label      string         `json:"label"`
+	Type       QuestionType   `json:"type"`
+	Responses  int            `json:"responses"`
+	Counts     map[string]int `json:"counts,omitempty"`
+	Sum        *float64       `json:"sum,omitempty"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+// SectionSchema is the content contract of a template section type,
+// published as a JSON Schema document for section editors.
+type SectionSchema struct {
+	Type   string                 `json:"type"`
+	Schema map[string]interface{} `json:"schema"`
+}
+
+// FieldError reports one invalid field of a request, addressed by a path
+// such as "accounts[0].number".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// EventSession is one part of a multi-session event, e.g. "Akad" and
+// "Resepsi", each with its own time and venue. Guests pick the sessions
+// they attend in Guest.SessionIDs.
+type EventSession struct {
+	ID           uuid.UUID  `db:"id" json:"id"`
+	EventID      uuid.UUID  `db:"event_id" json:"event_id"`
+	Name         string     `db:"name" json:"name"`
+	StartsAt     time.Time  `db:"starts_at" json:"starts_at"`
+	EndsAt       *time.Time `db:"ends_at" json:"ends_at"`
+	VenueName    *string    `db:"venue_name" json:"venue_name"`
+	VenueAddress *string    `db:"venue_address" json:"venue_address"`
+	MapURL       *string    `db:"map_url" json:"map_url"`
+	SortOrder    int        `db:"sort_order" json:"sort_order"`
+	// Restricts the session and its venue to these guest groups / guest
+	// IDs; both empty means every visitor sees it
+	VisibleGroups   pq.StringArray `db:"visible_groups" json:"visible_groups,omitempty"`
+	VisibleGuestIDs pq.StringArray `db:"visible_guest_ids" json:"visible_guest_ids,omitempty"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+}
+
+type CreateSessionRequest struct {
+	Name         string  `json:"name" binding:"required,min=1,max=100"`
+	StartsAt     string  `json:"starts_at" binding:"required"`
+	EndsAt       *string `json:"ends_at"`
+	VenueName    *string `json:"venue_name"`
+	VenueAddress *string `json:"venue_address"`
+	MapURL       *string `json:"map_url"`
+	SortOrder    int     `json:"sort_order"`
+	// Audience; leave empty for a public session
+	VisibleGroups   []string `json:"visible_groups"`
+	VisibleGuestIDs []string `json:"visible_guest_ids"`
+}
+
+type UpdateSessionRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	StartsAt *string `json:"starts_at"`
+	// EndsAt is RFC3339; an empty string removes the end time.
+	EndsAt       *string `json:"ends_at"`
+	VenueName    *string `json:"venue_name"`
+	VenueAddress *string `json:"venue_address"`
+	MapURL       *string `json:"map_url"`
+	SortOrder    *int    `json:"sort_order"`
+	// Audience; nil keeps it, empty lists make the session public again
+	VisibleGroups   []string `json:"visible_groups"`
+	VisibleGuestIDs []string `json:"visible_guest_ids"`
+}
+
+// SessionStats counts the attending guests of one session.
+type SessionStats struct {
+	SessionID      uuid.UUID `db:"session_id" json:"session_id"`
+	Name           string    `db:"name" json:"name"`
+	TotalAttending int       `db:"total_attending" json:"total_attending"`
+	TotalHeadcount int       `db:"total_headcount" json:"total_headcount"`
+}
@@ -9,12 +9,16 @@ import (
 )
 
 type Template struct {
-	ID           uuid.UUID        `db:"id" json:"id"`
-	Name         string           `db:"name" json:"name"`
-	Category     string           `db:"category" json:"category"`
-	ThumbnailURL *string          `db:"thumbnail_url" json:"thumbnail_url"`
-	IsActive     bool             `db:"is_active" json:"is_active"`
-	CreatedAt    time.Time        `db:"created_at" json:"created_at"`
+	ID           uuid.UUID         `db:"id" json:"id"`
+	Name         string            `db:"name" json:"name"`
+	Category     string            `db:"category" json:"category"`
+	ThumbnailURL *string           `db:"thumbnail_url" json:"thumbnail_url"`
+	ThumbnailKey *string           `db:"thumbnail_key" json:"-"`
+	IsActive     bool              `db:"is_active" json:"is_active"`
+	SortOrder    int               `db:"sort_order" json:"sort_order"`
+	Version      int               `db:"version" json:"version"` // bumped on every section change
+	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time         `db:"updated_at" json:"updated_at"`
 	Sections     []TemplateSection `db:"-" json:"sections,omitempty"`
 }
 
@@ -27,8 +31,74 @@ type TemplateSection struct {
 	SortOrder      int             `db:"sort_order" json:"sort_order"`
 }
 
+// TemplateVersion is a snapshot of a template's sections as they were at
+// one version, kept so events can be upgraded from it.
+type TemplateVersion struct {
+	TemplateID uuid.UUID       `db:"template_id" json:"template_id"`
+	Version    int             `db:"version" json:"version"`
+	Sections   json.RawMessage `db:"sections" json:"sections"` // []TemplateSection
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Admin requests
+
+type CreateTemplateRequest struct {
+	Name     string `json:"name" binding:"required,min=2,max=150"`
+	Category string `json:"category" binding:"required,max=50"`
+	// New templates stay hidden from users until activated
+	IsActive bool `json:"is_active"`
+}
+
+type UpdateTemplateRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=2,max=150"`
+	Category *string `json:"category" binding:"omitempty,max=50"`
+	// IsActive false hides the template from new events; existing events
+	// keep using it
+	IsActive *bool `json:"is_active"`
+}
+
+// ReorderTemplatesRequest lists templates in their new catalog order.
+// Templates left out keep their relative order after the listed ones.
+type ReorderTemplatesRequest struct {
+	TemplateIDs []string `json:"template_ids" binding:"required,min=1"`
+}
+
+type CreateTemplateSectionRequest struct {
+	Name           string          `json:"name" binding:"required,max=100"`
+	Type           string          `json:"type" binding:"required,max=50"`
+	DefaultContent json.RawMessage `json:"default_content"`
+}
+
+type UpdateTemplateSectionRequest struct {
+	Name           *string         `json:"name" binding:"omitempty,max=100"`
+	Type           *string         `json:"type" binding:"omitempty,max=50"`
+	DefaultContent json.RawMessage `json:"default_content"`
+}
+
+// ReorderTemplateSectionsRequest lists sections in their new order, as
+// ReorderTemplatesRequest does for templates.
+type ReorderTemplateSectionsRequest struct {
+	SectionIDs []string `json:"section_ids" binding:"required,min=1"`
+}
+
 type TemplateRepository interface {
-	FindAll(ctx context.Context, category string) ([]Template, error)
+	// FindAll lists templates in catalog order; inactive ones only when
+	// includeInactive is set
+	FindAll(ctx context.Context, category string, includeInactive bool) ([]Template, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*Template, error)
+	Create(ctx context.Context, tmpl *Template) error
+	Update(ctx context.Context, tmpl *Template) error
+	Reorder(ctx context.Context, templateIDs []uuid.UUID) error
+
 	FindSectionsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateSection, error)
+	// CreateSection, UpdateSection, DeleteSection and ReorderSections each
+	// publish a new template version along with the change
+	CreateSection(ctx context.Context, section *TemplateSection) error
+	UpdateSection(ctx context.Context, section *TemplateSection) error
+	DeleteSection(ctx context.Context, templateID, id uuid.UUID) error
+	// SectionInUse reports whether any event was created with the section
+	SectionInUse(ctx context.Context, id uuid.UUID) (bool, error)
+	ReorderSections(ctx context.Context, templateID uuid.UUID, sectionIDs []uuid.UUID) error
+
+	FindVersion(ctx context.Context, templateID uuid.UUID, version int) (*TemplateVersion, error)
 }
@@ -7,11 +7,21 @@ import (
 	"github.com/google/uuid"
 )
 
+// UserRole is a site-wide role, unlike the per-event CollaboratorRole.
+type UserRole string
+
+const (
+	UserRoleUser UserRole = "user"
+	// UserRoleAdmin manages the template catalog
+	UserRoleAdmin UserRole = "admin"
+)
+
 type User struct {
 	ID           uuid.UUID `db:"id" json:"id"`
 	Name         string    `db:"name" json:"name"`
 	Email        string    `db:"email" json:"email"`
 	PasswordHash string    `db:"password_hash" json:"-"`
+	Role         UserRole  `db:"role" json:"role"`
 	CreatedAt    time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/service"
+	"github.com/galihaleanda/event-invitation/internal/utils"
+)
+
+const qrCodeSize = 512
+
+type CheckInHandler struct {
+	checkInService service.CheckInService
+}
+
+func NewCheckInHandler(checkInService service.CheckInService) *CheckInHandler {
+	return &CheckInHandler{checkInService: checkInService}
+}
+
+// GET /events/:id/guests/:guestId/qr?format=png|svg  (protected - owner only)
+func (h *CheckInHandler) GetGuestQR(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	guestID, err := uuid.Parse(c.Param("guestId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid guest id")
+		return
+	}
+
+	token, err := h.checkInService.TokenForGuest(c.Request.Context(), getUserID(c), eventID, guestID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	respondQRCode(c, token)
+}
+
+// GET /rsvp/:code/qr?format=png|svg  (public - guest's own check-in code)
+func (h *CheckInHandler) GetMyQR(c *gin.Context) {
+	token, err := h.checkInService.TokenForCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	respondQRCode(c, token)
+}
+
+// POST /events/:id/checkin  (protected - owner only)
+func (h *CheckInHandler) CheckIn(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req domain.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.checkInService.CheckIn(c.Request.Context(), getUserID(c), eventID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	if result.AlreadyCheckedIn {
+		utils.RespondSuccess(c, http.StatusOK, "guest already checked in", result)
+		return
+	}
+	utils.RespondOK(c, result)
+}
+
+func respondQRCode(c *gin.Context, token string) {
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+	switch c.DefaultQuery("format", "png") {
+	case "png":
+		body, err = utils.QRCodePNG(token, qrCodeSize)
+		contentType = "image/png"
+	case "svg":
+		body, err = utils.QRCodeSVG(token)
+		contentType = "image/svg+xml"
+	default:
+		utils.RespondError(c, http.StatusBadRequest, "format must be png or svg")
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "failed to generate qr code")
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Data(http.StatusOK, contentType, body)
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/service"
+	"github.com/galihaleanda/event-invitation/internal/utils"
+)
+
+type CollaboratorHandler struct {
+	collaboratorService service.CollaboratorService
+}
+
+func NewCollaboratorHandler(collaboratorService service.CollaboratorService) *CollaboratorHandler {
+	return &CollaboratorHandler{collaboratorService: collaboratorService}
+}
+
+// GET /events/:id/collaborators
+func (h *CollaboratorHandler) List(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	collaborators, err := h.collaboratorService.List(c.Request.Context(), getUserID(c), eventID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, collaborators)
+}
+
+// POST /events/:id/collaborators
+func (h *CollaboratorHandler) Invite(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req domain.InviteCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collaborator, err := h.collaboratorService.Invite(c.Request.Context(), getUserID(c), eventID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondCreated(c, collaborator)
+}
+
+// PATCH /events/:id/collaborators/:collaboratorId
+func (h *CollaboratorHandler) UpdateRole(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	collaboratorID, err := uuid.Parse(c.Param("collaboratorId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid collaborator id")
+		return
+	}
+
+	var req domain.UpdateCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collaborator, err := h.collaboratorService.UpdateRole(c.Request.Context(), getUserID(c), eventID, collaboratorID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, collaborator)
+}
+
+// DELETE /events/:id/collaborators/:collaboratorId
+func (h *CollaboratorHandler) Remove(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	collaboratorID, err := uuid.Parse(c.Param("collaboratorId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid collaborator id")
+		return
+	}
+
+	if err := h.collaboratorService.Remove(c.Request.Context(), getUserID(c), eventID, collaboratorID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, nil)
+}
+
+// GET /invitations
+func (h *CollaboratorHandler) MyInvitations(c *gin.Context) {
+	invitations, err := h.collaboratorService.MyInvitations(c.Request.Context(), getUserID(c))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, invitations)
+}
+
+// POST /invitations/:id/accept
+func (h *CollaboratorHandler) Accept(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid invitation id")
+		return
+	}
+
+	invitation, err := h.collaboratorService.Accept(c.Request.Context(), getUserID(c), id)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, invitation)
+}
+
+// DELETE /invitations/:id
+func (h *CollaboratorHandler) Decline(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid invitation id")
+		return
+	}
+
+	if err := h.collaboratorService.Decline(c.Request.Context(), getUserID(c), id); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, nil)
+}
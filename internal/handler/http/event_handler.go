@@ -57,18 +57,12 @@ func (h *EventHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	event, err := h.eventService.GetByID(c.Request.Context(), id)
+	event, err := h.eventService.GetByID(c.Request.Context(), getUserID(c), id)
 	if err != nil {
 		handleServiceError(c, err)
 		return
 	}
 
-	// Only owner can view unpublished events
-	if !event.IsPublished && event.UserID != getUserID(c) {
-		utils.RespondError(c, http.StatusForbidden, "forbidden")
-		return
-	}
-
 	utils.RespondOK(c, event)
 }
 
@@ -151,6 +145,59 @@ func (h *EventHandler) UpdateTheme(c *gin.Context) {
 	utils.RespondOK(c, theme)
 }
 
+// GET /events/:id/music
+func (h *EventHandler) GetMusic(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	music, err := h.eventService.GetMusic(c.Request.Context(), getUserID(c), id)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, music)
+}
+
+// PUT /events/:id/music
+func (h *EventHandler) UpdateMusic(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.UpdateMusicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	music, err := h.eventService.UpdateMusic(c.Request.Context(), getUserID(c), id, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, music)
+}
+
+// DELETE /events/:id/music
+func (h *EventHandler) DeleteMusic(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.eventService.DeleteMusic(c.Request.Context(), getUserID(c), id); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, nil)
+}
+
 // PATCH /events/:id/sections/:sectionId
 func (h *EventHandler) UpdateSection(c *gin.Context) {
 	eventID, err := uuid.Parse(c.Param("id"))
@@ -178,10 +225,228 @@ func (h *EventHandler) UpdateSection(c *gin.Context) {
 	utils.RespondOK(c, section)
 }
 
-// GET /e/:slug  (public)
+// GET /events/:id/template-upgrade
+func (h *EventHandler) GetTemplateUpgrade(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	upgrade, err := h.eventService.GetTemplateUpgrade(c.Request.Context(), getUserID(c), id)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, upgrade)
+}
+
+// POST /events/:id/template-upgrade
+func (h *EventHandler) UpgradeTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	upgrade, err := h.eventService.UpgradeTemplate(c.Request.Context(), getUserID(c), id)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, upgrade)
+}
+
+// GET /events/:id/stats
+func (h *EventHandler) GetStats(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	stats, err := h.eventService.GetStats(c.Request.Context(), getUserID(c), id)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, stats)
+}
+
+// GET /events/:id/questions
+func (h *EventHandler) GetQuestions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	questions, err := h.eventService.GetQuestions(c.Request.Context(), getUserID(c), id)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, questions)
+}
+
+// POST /events/:id/questions
+func (h *EventHandler) CreateQuestion(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.CreateQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	question, err := h.eventService.CreateQuestion(c.Request.Context(), getUserID(c), id, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondCreated(c, question)
+}
+
+// PATCH /events/:id/questions/:questionId
+func (h *EventHandler) UpdateQuestion(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	questionID, err := uuid.Parse(c.Param("questionId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid question id")
+		return
+	}
+
+	var req domain.UpdateQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	question, err := h.eventService.UpdateQuestion(c.Request.Context(), getUserID(c), eventID, questionID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, question)
+}
+
+// DELETE /events/:id/questions/:questionId
+func (h *EventHandler) DeleteQuestion(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	questionID, err := uuid.Parse(c.Param("questionId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid question id")
+		return
+	}
+
+	if err := h.eventService.DeleteQuestion(c.Request.Context(), getUserID(c), eventID, questionID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, nil)
+}
+
+// GET /events/:id/sessions
+func (h *EventHandler) GetSessions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	sessions, err := h.eventService.GetSessions(c.Request.Context(), getUserID(c), id)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, sessions)
+}
+
+// POST /events/:id/sessions
+func (h *EventHandler) CreateSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.CreateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err := h.eventService.CreateSession(c.Request.Context(), getUserID(c), id, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondCreated(c, session)
+}
+
+// PATCH /events/:id/sessions/:sessionId
+func (h *EventHandler) UpdateSession(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	var req domain.UpdateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err := h.eventService.UpdateSession(c.Request.Context(), getUserID(c), eventID, sessionID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, session)
+}
+
+// DELETE /events/:id/sessions/:sessionId
+func (h *EventHandler) DeleteSession(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	if err := h.eventService.DeleteSession(c.Request.Context(), getUserID(c), eventID, sessionID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, nil)
+}
+
+// GET /e/:slug?to=<guest_code>  (public)
 func (h *EventHandler) GetPublic(c *gin.Context) {
 	slug := c.Param("slug")
-	resp, err := h.eventService.GetBySlug(c.Request.Context(), slug)
+	resp, err := h.eventService.GetBySlug(c.Request.Context(), slug, c.Query("to"))
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -191,6 +456,10 @@ func (h *EventHandler) GetPublic(c *gin.Context) {
 
 func handleServiceError(c *gin.Context, err error) {
 	if appErr, ok := err.(*service.AppError); ok {
+		if appErr.Data != nil {
+			utils.RespondErrorWithData(c, appErr.Code, appErr.Message, appErr.Data)
+			return
+		}
 		utils.RespondError(c, appErr.Code, appErr.Message)
 		return
 	}
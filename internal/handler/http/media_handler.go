@@ -1,31 +1,28 @@
 package http
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"path/filepath"
-	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/galihaleanda/event-invitation/internal/config"
 	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/service"
 	"github.com/galihaleanda/event-invitation/internal/utils"
 )
 
+// multipartOverhead leaves room for the form fields and part headers
+// around an uploaded file.
+const multipartOverhead = 1 << 20
+
 type MediaHandler struct {
-	mediaRepo   domain.MediaRepository
-	eventRepo   domain.EventRepository
-	storageCfg  config.StorageConfig
+	mediaService service.MediaService
+	// maxGuestUpload caps the photo size guests may share
+	maxGuestUpload int64
 }
 
-func NewMediaHandler(mediaRepo domain.MediaRepository, eventRepo domain.EventRepository, cfg *config.Config) *MediaHandler {
-	return &MediaHandler{
-		mediaRepo:  mediaRepo,
-		eventRepo:  eventRepo,
-		storageCfg: cfg.Storage,
-	}
+func NewMediaHandler(mediaService service.MediaService, maxGuestUpload int64) *MediaHandler {
+	return &MediaHandler{mediaService: mediaService, maxGuestUpload: maxGuestUpload}
 }
 
 // POST /events/:id/media
@@ -36,68 +33,195 @@ func (h *MediaHandler) Upload(c *gin.Context) {
 		return
 	}
 
-	// Verify ownership
-	event, err := h.eventRepo.FindByID(c.Request.Context(), eventID)
-	if err != nil || event.UserID != getUserID(c) {
-		utils.RespondError(c, http.StatusForbidden, "forbidden")
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "file is required")
 		return
 	}
 
-	file, header, err := c.Request.FormFile("file")
+	media, err := h.mediaService.Upload(c.Request.Context(), getUserID(c), eventID, header)
 	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "file is required")
+		handleServiceError(c, err)
 		return
 	}
-	defer file.Close()
+	utils.RespondCreated(c, media)
+}
 
-	// Determine media type from extension
-	ext := strings.ToLower(filepath.Ext(header.Filename))
-	mediaType := domain.MediaTypeImage
-	switch ext {
-	case ".mp4", ".mov", ".avi":
-		mediaType = domain.MediaTypeVideo
-	case ".mp3", ".wav", ".ogg":
-		mediaType = domain.MediaTypeAudio
+// GET /events/:id/media?status=pending
+func (h *MediaHandler) GetByEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
 	}
 
-	// Save file
-	filename := fmt.Sprintf("%d-%s%s", time.Now().UnixNano(), uuid.New().String()[:8], ext)
-	savePath := filepath.Join(h.storageCfg.BasePath, "events", eventID.String(), filename)
+	media, err := h.mediaService.GetByEvent(c.Request.Context(), getUserID(c), eventID, c.Query("status"))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, media)
+}
 
-	if err := c.SaveUploadedFile(header, savePath); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "failed to save file")
+// POST /events/:id/media/uploads  (reserve a direct upload slot)
+func (h *MediaHandler) CreateUpload(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
 		return
 	}
 
-	fileURL := fmt.Sprintf("%s/events/%s/%s", h.storageCfg.BaseURL, eventID.String(), filename)
+	var req domain.CreateMediaUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	media := &domain.Media{
-		ID:        uuid.New(),
-		EventID:   eventID,
-		FileURL:   fileURL,
-		MediaType: mediaType,
-		CreatedAt: time.Now(),
+	upload, err := h.mediaService.CreateUpload(c.Request.Context(), getUserID(c), eventID, req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
 	}
+	utils.RespondCreated(c, upload)
+}
 
-	if err := h.mediaRepo.Create(c.Request.Context(), media); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "failed to save media")
+// POST /events/:id/media/uploads/:uploadId/confirm
+func (h *MediaHandler) ConfirmUpload(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	uploadID, err := uuid.Parse(c.Param("uploadId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid upload id")
 		return
 	}
 
+	media, err := h.mediaService.ConfirmUpload(c.Request.Context(), getUserID(c), eventID, uploadID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
 	utils.RespondCreated(c, media)
 }
 
-// GET /events/:id/media
-func (h *MediaHandler) GetByEvent(c *gin.Context) {
+// GET /events/:id/media/:mediaId/download  (redirects to a short-lived link)
+func (h *MediaHandler) Download(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	mediaID, err := uuid.Parse(c.Param("mediaId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid media id")
+		return
+	}
+
+	url, err := h.mediaService.DownloadURL(c.Request.Context(), getUserID(c), eventID, mediaID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, url)
+}
+
+// POST /e/:slug/photos  (public - guests share their photos)
+func (h *MediaHandler) GuestUpload(c *gin.Context) {
+	// Cut off oversized bodies before they are spooled to disk
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxGuestUpload+multipartOverhead)
+
+	var req domain.GuestUploadRequest
+	if err := c.ShouldBind(&req); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			utils.RespondError(c, http.StatusRequestEntityTooLarge, "photo is too large")
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "file is required")
+		return
+	}
+
+	media, err := h.mediaService.GuestUpload(c.Request.Context(), c.Param("slug"), &req, header)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, http.StatusCreated, "photo submitted for approval", media)
+}
+
+// POST /events/:id/media/:mediaId/approve
+func (h *MediaHandler) Approve(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	mediaID, err := uuid.Parse(c.Param("mediaId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid media id")
+		return
+	}
+
+	media, err := h.mediaService.Approve(c.Request.Context(), getUserID(c), eventID, mediaID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, media)
+}
+
+// PATCH /events/:id/media/:mediaId
+func (h *MediaHandler) Update(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	mediaID, err := uuid.Parse(c.Param("mediaId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid media id")
+		return
+	}
+
+	var req domain.UpdateMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	media, err := h.mediaService.Update(c.Request.Context(), getUserID(c), eventID, mediaID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, media)
+}
+
+// PUT /events/:id/media/order
+func (h *MediaHandler) Reorder(c *gin.Context) {
 	eventID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
 		return
 	}
 
-	media, err := h.mediaRepo.FindByEventID(c.Request.Context(), eventID)
+	var req domain.ReorderMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	media, err := h.mediaService.Reorder(c.Request.Context(), getUserID(c), eventID, &req)
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "failed to get media")
+		handleServiceError(c, err)
 		return
 	}
 	utils.RespondOK(c, media)
@@ -105,14 +229,104 @@ func (h *MediaHandler) GetByEvent(c *gin.Context) {
 
 // DELETE /events/:id/media/:mediaId
 func (h *MediaHandler) Delete(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
 	mediaID, err := uuid.Parse(c.Param("mediaId"))
 	if err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "invalid media id")
 		return
 	}
 
-	if err := h.mediaRepo.Delete(c.Request.Context(), mediaID); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "failed to delete media")
+	if err := h.mediaService.Delete(c.Request.Context(), getUserID(c), eventID, mediaID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, nil)
+}
+
+// GET /events/:id/albums
+func (h *MediaHandler) GetAlbums(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	albums, err := h.mediaService.GetAlbums(c.Request.Context(), getUserID(c), eventID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, albums)
+}
+
+// POST /events/:id/albums
+func (h *MediaHandler) CreateAlbum(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req domain.CreateAlbumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	album, err := h.mediaService.CreateAlbum(c.Request.Context(), getUserID(c), eventID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondCreated(c, album)
+}
+
+// PATCH /events/:id/albums/:albumId
+func (h *MediaHandler) UpdateAlbum(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	albumID, err := uuid.Parse(c.Param("albumId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid album id")
+		return
+	}
+
+	var req domain.UpdateAlbumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	album, err := h.mediaService.UpdateAlbum(c.Request.Context(), getUserID(c), eventID, albumID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, album)
+}
+
+// DELETE /events/:id/albums/:albumId
+func (h *MediaHandler) DeleteAlbum(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	albumID, err := uuid.Parse(c.Param("albumId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid album id")
+		return
+	}
+
+	if err := h.mediaService.DeleteAlbum(c.Request.Context(), getUserID(c), eventID, albumID); err != nil {
+		handleServiceError(c, err)
 		return
 	}
 	utils.RespondOK(c, nil)
@@ -1,6 +1,8 @@
 package http
 
 import (
+	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -63,3 +65,259 @@ func (h *RSVPHandler) GetGuests(c *gin.Context) {
 	}
 	utils.RespondOK(c, guests)
 }
+
+// POST /events/:id/guests  (protected - owner only)
+func (h *RSVPHandler) CreateGuest(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req domain.CreateGuestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	guest, err := h.rsvpService.CreateGuest(c.Request.Context(), getUserID(c), eventID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondCreated(c, guest)
+}
+
+// PATCH /events/:id/guests/:guestId  (protected - owner only)
+func (h *RSVPHandler) UpdateGuest(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	guestID, err := uuid.Parse(c.Param("guestId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid guest id")
+		return
+	}
+
+	var req domain.UpdateGuestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	guest, err := h.rsvpService.UpdateGuest(c.Request.Context(), getUserID(c), eventID, guestID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, guest)
+}
+
+// DELETE /events/:id/guests/:guestId  (protected - owner only)
+func (h *RSVPHandler) DeleteGuest(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	guestID, err := uuid.Parse(c.Param("guestId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid guest id")
+		return
+	}
+
+	if err := h.rsvpService.DeleteGuest(c.Request.Context(), getUserID(c), eventID, guestID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, nil)
+}
+
+// POST /events/:id/guests/import  (protected - owner only)
+func (h *RSVPHandler) ImportGuests(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.rsvpService.ImportGuests(c.Request.Context(), getUserID(c), eventID, header.Filename, file)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	if len(result.Errors) > 0 {
+		utils.RespondErrorWithData(c, http.StatusUnprocessableEntity, "some rows are invalid, nothing was imported", result)
+		return
+	}
+	utils.RespondCreated(c, result)
+}
+
+// GET /events/:id/guests/export?format=csv|xlsx|pdf  (protected - owner only)
+func (h *RSVPHandler) ExportGuests(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	contentTypes := map[string]string{
+		"csv":  "text/csv; charset=utf-8",
+		"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"pdf":  "application/pdf",
+	}
+	contentType, ok := contentTypes[format]
+	if !ok {
+		utils.RespondError(c, http.StatusBadRequest, "format must be csv, xlsx or pdf")
+		return
+	}
+
+	export, err := h.rsvpService.ExportGuests(c.Request.Context(), getUserID(c), eventID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("guests-%s.%s", export.Event.Slug, format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	switch format {
+	case "csv":
+		err = utils.WriteCSV(c.Writer, export.Header, export.Rows)
+	case "xlsx":
+		sheets := []utils.XLSXSheet{{Name: "Guests", Header: export.Header, Rows: export.Rows}}
+		if len(export.Summary) > 0 {
+			sheets = append(sheets, utils.XLSXSheet{Name: "Answers", Header: []string{"Question", "Answer", "Count"}, Rows: export.Summary})
+		}
+		err = utils.WriteXLSX(c.Writer, sheets...)
+	case "pdf":
+		err = utils.WriteTablePDF(c.Writer, export.Event.Title, attendanceSubtitle(export.Event), attendanceColumns(export.Header), export.Rows)
+	}
+	if err != nil {
+		log.Printf("failed to export guests for event %s: %v", eventID, err)
+	}
+}
+
+// attendanceColumns sizes the export columns for a landscape A4 sheet,
+// shrinking them proportionally when custom question columns are added.
+func attendanceColumns(header []string) []utils.PDFColumn {
+	const pageWidth = 277.0
+
+	widths := map[string]float64{
+		"Name":          55,
+		"Phone":         35,
+		"Group":         25,
+		"RSVP Status":   22,
+		"Party Size":    20,
+		"Attendees":     20,
+		"Message":       60,
+		"Guest Code":    28,
+		"Created At":    32,
+		"Checked In At": 32,
+		"Sessions":      40,
+	}
+	columns := make([]utils.PDFColumn, len(header))
+	total := 0.0
+	for i, title := range header {
+		width, ok := widths[title]
+		if !ok {
+			width = 30
+		}
+		columns[i] = utils.PDFColumn{Title: title, Width: width}
+		total += width
+	}
+	if total > pageWidth {
+		for i := range columns {
+			columns[i].Width *= pageWidth / total
+		}
+	}
+	return columns
+}
+
+func attendanceSubtitle(event *domain.Event) string {
+	subtitle := "Guest attendance sheet - " + event.EventDate.Format("02 Jan 2006 15:04")
+	if event.LocationName != nil && *event.LocationName != "" {
+		subtitle += " - " + *event.LocationName
+	}
+	return subtitle
+}
+
+// POST /events/:id/guests/:guestId/promote  (protected - owner only)
+func (h *RSVPHandler) PromoteGuest(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	guestID, err := uuid.Parse(c.Param("guestId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid guest id")
+		return
+	}
+
+	guest, err := h.rsvpService.PromoteGuest(c.Request.Context(), getUserID(c), eventID, guestID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, guest)
+}
+
+// GET /rsvp/:code  (public - guest's own rsvp)
+func (h *RSVPHandler) GetMyRSVP(c *gin.Context) {
+	guest, err := h.rsvpService.GetByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, guest)
+}
+
+// PATCH /rsvp/:code  (public - guest changes or cancels their rsvp)
+func (h *RSVPHandler) UpdateMyRSVP(c *gin.Context) {
+	var req domain.UpdateRSVPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	guest, err := h.rsvpService.UpdateByCode(c.Request.Context(), c.Param("code"), &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, guest)
+}
+
+// GET /events/:id/guests/:guestId/history  (protected - owner only)
+func (h *RSVPHandler) GetGuestHistory(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	guestID, err := uuid.Parse(c.Param("guestId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid guest id")
+		return
+	}
+
+	changes, err := h.rsvpService.GetGuestHistory(c.Request.Context(), getUserID(c), eventID, guestID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, changes)
+}
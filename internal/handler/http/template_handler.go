@@ -5,6 +5,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/handler/web"
 	"github.com/galihaleanda/event-invitation/internal/service"
 	"github.com/galihaleanda/event-invitation/internal/utils"
 )
@@ -19,7 +21,7 @@ func NewTemplateHandler(templateService service.TemplateService) *TemplateHandle
 
 func (h *TemplateHandler) GetAll(c *gin.Context) {
 	category := c.Query("category")
-	templates, err := h.templateService.GetAll(c.Request.Context(), category)
+	templates, err := h.templateService.GetAll(c.Request.Context(), category, false)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "failed to get templates")
 		return
@@ -34,7 +36,7 @@ func (h *TemplateHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	tmpl, err := h.templateService.GetByID(c.Request.Context(), id)
+	tmpl, err := h.templateService.GetByID(c.Request.Context(), id, false)
 	if err != nil {
 		if appErr, ok := err.(*service.AppError); ok {
 			utils.RespondError(c, appErr.Code, appErr.Message)
@@ -45,3 +47,225 @@ func (h *TemplateHandler) GetByID(c *gin.Context) {
 	}
 	utils.RespondOK(c, tmpl)
 }
+
+// GET /templates/section-types
+func (h *TemplateHandler) GetSectionSchemas(c *gin.Context) {
+	utils.RespondOK(c, h.templateService.GetSectionSchemas())
+}
+
+// Admin endpoints (/admin/templates), behind middleware.AdminOnly
+
+// GET /admin/templates?category=  (includes inactive templates)
+func (h *TemplateHandler) AdminGetAll(c *gin.Context) {
+	templates, err := h.templateService.GetAll(c.Request.Context(), c.Query("category"), true)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, templates)
+}
+
+// GET /admin/templates/:id
+func (h *TemplateHandler) AdminGetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	tmpl, err := h.templateService.GetByID(c.Request.Context(), id, true)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, tmpl)
+}
+
+// POST /admin/templates
+func (h *TemplateHandler) Create(c *gin.Context) {
+	var req domain.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tmpl, err := h.templateService.Create(c.Request.Context(), &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondCreated(c, tmpl)
+}
+
+// PATCH /admin/templates/:id  (is_active=false deactivates)
+func (h *TemplateHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tmpl, err := h.templateService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, tmpl)
+}
+
+// PUT /admin/templates/order
+func (h *TemplateHandler) Reorder(c *gin.Context) {
+	var req domain.ReorderTemplatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	templates, err := h.templateService.Reorder(c.Request.Context(), &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, templates)
+}
+
+// POST /admin/templates/:id/thumbnail  (multipart "file")
+func (h *TemplateHandler) UploadThumbnail(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "file is required")
+		return
+	}
+
+	tmpl, err := h.templateService.UploadThumbnail(c.Request.Context(), id, header)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, tmpl)
+}
+
+// GET /admin/templates/:id/preview  (HTML)
+func (h *TemplateHandler) Preview(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	tmpl, err := h.templateService.GetByID(c.Request.Context(), id, true)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	body, err := web.RenderTemplatePreview(tmpl)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "failed to render preview")
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", body)
+}
+
+// POST /admin/templates/:id/sections
+func (h *TemplateHandler) CreateSection(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.CreateTemplateSectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	section, err := h.templateService.CreateSection(c.Request.Context(), id, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondCreated(c, section)
+}
+
+// PATCH /admin/templates/:id/sections/:sectionId
+func (h *TemplateHandler) UpdateSection(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+	sectionID, err := uuid.Parse(c.Param("sectionId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid section id")
+		return
+	}
+
+	var req domain.UpdateTemplateSectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	section, err := h.templateService.UpdateSection(c.Request.Context(), id, sectionID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, section)
+}
+
+// DELETE /admin/templates/:id/sections/:sectionId
+func (h *TemplateHandler) DeleteSection(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+	sectionID, err := uuid.Parse(c.Param("sectionId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid section id")
+		return
+	}
+
+	if err := h.templateService.DeleteSection(c.Request.Context(), id, sectionID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, nil)
+}
+
+// PUT /admin/templates/:id/sections/order
+func (h *TemplateHandler) ReorderSections(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.ReorderTemplateSectionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sections, err := h.templateService.ReorderSections(c.Request.Context(), id, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, sections)
+}
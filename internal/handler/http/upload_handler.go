@@ -0,0 +1,60 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/storage"
+	"github.com/galihaleanda/event-invitation/internal/utils"
+)
+
+// UploadHandler receives direct uploads for the local storage driver,
+// standing in for the presigned PUT an S3 bucket would accept.
+type UploadHandler struct {
+	storage   *storage.Local
+	mediaRepo domain.MediaRepository
+}
+
+func NewUploadHandler(local *storage.Local, mediaRepo domain.MediaRepository) *UploadHandler {
+	return &UploadHandler{storage: local, mediaRepo: mediaRepo}
+}
+
+// PUT /uploads/*filepath?expires=...&size=...&signature=...
+func (h *UploadHandler) Put(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("filepath"), "/")
+
+	size, err := h.storage.VerifyUpload(key, c.Request.URL.Query())
+	if err != nil {
+		utils.RespondError(c, http.StatusForbidden, "invalid or expired upload url")
+		return
+	}
+
+	// The URL only works while its slot is open and empty, so a confirmed
+	// (and sniffed, stripped) file can never be overwritten. Create is
+	// exclusive, so of two concurrent PUTs only one writes the file.
+	upload, err := h.mediaRepo.FindUploadByKey(c.Request.Context(), key)
+	if err != nil || upload == nil || time.Now().After(upload.ExpiresAt) {
+		utils.RespondError(c, http.StatusForbidden, "invalid or expired upload url")
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, size)
+	if err := h.storage.Create(c.Request.Context(), key, body, size, c.ContentType()); err != nil {
+		if errors.Is(err, storage.ErrExists) {
+			utils.RespondError(c, http.StatusConflict, "file has already been uploaded")
+			return
+		}
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			utils.RespondError(c, http.StatusRequestEntityTooLarge, "file is larger than the declared size")
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "failed to store file")
+		return
+	}
+	utils.RespondOK(c, nil)
+}
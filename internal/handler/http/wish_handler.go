@@ -0,0 +1,97 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/service"
+	"github.com/galihaleanda/event-invitation/internal/utils"
+)
+
+type WishHandler struct {
+	wishService service.WishService
+}
+
+func NewWishHandler(wishService service.WishService) *WishHandler {
+	return &WishHandler{wishService: wishService}
+}
+
+// GET /e/:slug/wishes?page=1&limit=20  (public)
+func (h *WishHandler) GetPublic(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	wishes, err := h.wishService.GetPublic(c.Request.Context(), c.Param("slug"), page, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, wishes)
+}
+
+// GET /events/:id/wishes?status=pending  (protected - owner only)
+func (h *WishHandler) GetByEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	status := domain.MessageStatus(c.Query("status"))
+	guests, err := h.wishService.GetForOwner(c.Request.Context(), getUserID(c), eventID, status)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, guests)
+}
+
+// PATCH /events/:id/wishes/:guestId  (protected - owner only)
+func (h *WishHandler) Moderate(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	guestID, err := uuid.Parse(c.Param("guestId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid guest id")
+		return
+	}
+
+	var req domain.ModerateWishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	guest, err := h.wishService.Moderate(c.Request.Context(), getUserID(c), eventID, guestID, req.Action)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, guest)
+}
+
+// DELETE /events/:id/wishes/:guestId  (protected - owner only)
+func (h *WishHandler) Delete(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	guestID, err := uuid.Parse(c.Param("guestId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "invalid guest id")
+		return
+	}
+
+	if err := h.wishService.Delete(c.Request.Context(), getUserID(c), eventID, guestID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+	utils.RespondOK(c, nil)
+}
@@ -0,0 +1,109 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/service"
+)
+
+// PageHandler serves the invitation page as server-rendered HTML, for
+// visitors and crawlers that do not run the frontend app.
+type PageHandler struct {
+	eventService   service.EventService
+	wishService    service.WishService
+	previewService service.PreviewService
+	publicURL      string
+}
+
+func NewPageHandler(eventService service.EventService, wishService service.WishService, previewService service.PreviewService, publicURL string) *PageHandler {
+	return &PageHandler{
+		eventService:   eventService,
+		wishService:    wishService,
+		previewService: previewService,
+		publicURL:      publicURL,
+	}
+}
+
+// GET /:slug?to=<guest_code>
+func (h *PageHandler) Show(c *gin.Context) {
+	ctx := c.Request.Context()
+	slug := c.Param("slug")
+
+	resp, err := h.eventService.GetBySlug(ctx, slug, c.Query("to"))
+	if err != nil {
+		code := http.StatusInternalServerError
+		if appErr, ok := err.(*service.AppError); ok {
+			code = appErr.Code
+		}
+		renderError(c, code)
+		return
+	}
+
+	var wishes []domain.Wish
+	if page, err := h.wishService.GetPublic(ctx, slug, 1, 0); err == nil {
+		wishes = page.Items
+	}
+
+	// Shared links carry no guest code, so previews never name a guest
+	pageURL := h.publicURL + "/" + resp.Event.Slug
+	meta := Meta{
+		Title:       resp.Event.Title,
+		Description: service.PreviewDescription(resp.Event),
+		URL:         pageURL,
+		Image:       pageURL + "/preview.jpg?v=" + service.PreviewVersion(resp.Event, resp.Theme, resp.Cover),
+	}
+	if resp.Cover != nil {
+		meta.CoverImage = absoluteURL(h.publicURL, resp.Cover.FileURL)
+	}
+
+	body, err := renderPage(resp, wishes, meta)
+	if err != nil {
+		log.Printf("page %s: %v", slug, err)
+		renderError(c, http.StatusInternalServerError)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", body)
+}
+
+// GET /:slug/preview.jpg
+func (h *PageHandler) Preview(c *gin.Context) {
+	preview, err := h.previewService.GetBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		code := http.StatusInternalServerError
+		if appErr, ok := err.(*service.AppError); ok {
+			code = appErr.Code
+		}
+		c.AbortWithStatus(code)
+		return
+	}
+
+	etag := `"` + preview.Version + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=3600")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "image/jpeg", preview.Image)
+}
+
+// absoluteURL resolves file URLs served by this API (such as local
+// uploads) against the public URL.
+func absoluteURL(base, u string) string {
+	if strings.HasPrefix(u, "/") && !strings.HasPrefix(u, "//") {
+		return base + u
+	}
+	return u
+}
+
+func renderError(c *gin.Context, code int) {
+	c.Status(code)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := pages.ExecuteTemplate(c.Writer, "error", gin.H{"Code": code, "Text": http.StatusText(code)}); err != nil {
+		log.Printf("error page: %v", err)
+	}
+}
@@ -0,0 +1,331 @@
+package web
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// pages holds the page layout plus one "section:<type>" template per
+// section type; types without a template fall back to "section:default".
+var pages = template.Must(template.New("").Funcs(funcs).ParseFS(templateFS, "templates/*.html"))
+
+var funcs = template.FuncMap{
+	"text":  contentText,
+	"items": contentItems,
+	"date":  func(t time.Time) string { return t.Format("Monday, 2 January 2006") },
+	"clock": func(t time.Time) string { return t.Format("15:04") },
+	"iso":   func(t time.Time) string { return t.Format("2006-01-02T15:04:05") },
+	"deref": deref,
+}
+
+// Page is the data handed to the page layout.
+type Page struct {
+	*domain.PublicEventResponse
+	Meta     Meta
+	Style    Style
+	Blocks   []template.HTML
+	Photos   []Photo
+	Wishes   []domain.Wish
+	Title    string
+	CoverURL string
+	RSVP     RSVPForm
+}
+
+// Meta is the Open Graph / Twitter card metadata of the page. URLs are
+// absolute, as link preview crawlers require.
+type Meta struct {
+	Title       string
+	Description string
+	URL         string
+	Image       string
+	CoverImage  string
+}
+
+// Section is the data handed to a section template.
+type Section struct {
+	domain.EventSection
+	Data map[string]interface{}
+	Page *Page
+}
+
+// Photo is an approved gallery image with its resized variants.
+type Photo struct {
+	domain.Media
+	Src    string
+	SrcSet string
+}
+
+// RSVPForm fills the RSVP form, from the invited guest's current answer
+// when there is one.
+type RSVPForm struct {
+	Status       domain.RSVPStatus
+	Attendees    int
+	MaxAttendees int
+	Message      string
+	Questions    []FormQuestion
+	Sessions     []FormSession
+}
+
+// FormQuestion is a custom RSVP question with the guest's answer.
+type FormQuestion struct {
+	domain.EventQuestion
+	Value   string
+	Choices []string
+}
+
+// Chosen reports whether the guest picked option.
+func (q FormQuestion) Chosen(option string) bool {
+	for _, c := range q.Choices {
+		if c == option {
+			return true
+		}
+	}
+	return false
+}
+
+// FormSession is a session the guest can join.
+type FormSession struct {
+	domain.EventSession
+	Checked bool
+}
+
+// Style carries the event theme, validated for use inside <style>.
+type Style struct {
+	Vars      template.CSS
+	Custom    template.CSS
+	FontURL   string
+	HasCustom bool
+}
+
+// renderPage builds the page model and executes the layout.
+func renderPage(resp *domain.PublicEventResponse, wishes []domain.Wish, meta Meta) ([]byte, error) {
+	page := &Page{
+		PublicEventResponse: resp,
+		Meta:                meta,
+		Style:               themeStyle(resp.Theme),
+		Wishes:              wishes,
+		Title:               resp.Event.Title,
+		RSVP:                newRSVPForm(resp),
+	}
+	for _, m := range resp.Gallery {
+		if m.MediaType == "image" {
+			page.Photos = append(page.Photos, newPhoto(m))
+		}
+	}
+	if resp.Cover != nil {
+		page.CoverURL = newPhoto(*resp.Cover).Src
+	}
+
+	for _, s := range resp.Sections {
+		if !s.IsVisible {
+			continue
+		}
+		block, err := renderSection(page, s)
+		if err != nil {
+			return nil, err
+		}
+		page.Blocks = append(page.Blocks, block)
+	}
+
+	var buf bytes.Buffer
+	if err := pages.ExecuteTemplate(&buf, "page", page); err != nil {
+		return nil, fmt.Errorf("render page: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderSection executes the template registered for the section type.
+// The output comes from html/template, so it is already escaped.
+func renderSection(page *Page, s domain.EventSection) (template.HTML, error) {
+	data := map[string]interface{}{}
+	if len(s.Content) > 0 {
+		// Malformed content renders the section with defaults
+		_ = json.Unmarshal(s.Content, &data)
+	}
+
+	name := "section:" + s.Type
+	if pages.Lookup(name) == nil {
+		name = "section:default"
+	}
+
+	var buf bytes.Buffer
+	if err := pages.ExecuteTemplate(&buf, name, Section{EventSection: s, Data: data, Page: page}); err != nil {
+		return "", fmt.Errorf("render section %s: %w", s.Type, err)
+	}
+	return template.HTML(buf.String()), nil
+}
+
+func newRSVPForm(resp *domain.PublicEventResponse) RSVPForm {
+	form := RSVPForm{Attendees: 1, MaxAttendees: resp.Event.MaxPartySize}
+	answers := map[string]interface{}{}
+	chosen := map[string]bool{}
+
+	if g := resp.Guest; g != nil {
+		form.Status = g.RSVPStatus
+		form.MaxAttendees = g.PartySize
+		if g.Attendees > 0 {
+			form.Attendees = g.Attendees
+		}
+		form.Message = deref(g.Message)
+		if len(g.Answers) > 0 {
+			_ = json.Unmarshal(g.Answers, &answers)
+		}
+		for _, id := range g.SessionIDs {
+			chosen[id] = true
+		}
+	}
+
+	for _, q := range resp.Questions {
+		fq := FormQuestion{EventQuestion: q}
+		switch v := answers[q.ID.String()].(type) {
+		case string:
+			fq.Value = v
+			fq.Choices = []string{v}
+		case float64:
+			fq.Value = strconv.FormatFloat(v, 'f', -1, 64)
+		case []interface{}:
+			for _, c := range v {
+				if s, ok := c.(string); ok {
+					fq.Choices = append(fq.Choices, s)
+				}
+			}
+		}
+		form.Questions = append(form.Questions, fq)
+	}
+
+	// Everyone joins every session until they choose otherwise
+	for _, s := range resp.Sessions {
+		form.Sessions = append(form.Sessions, FormSession{
+			EventSession: s,
+			Checked:      len(chosen) == 0 || chosen[s.ID.String()],
+		})
+	}
+	return form
+}
+
+func newPhoto(m domain.Media) Photo {
+	p := Photo{Media: m, Src: m.FileURL}
+
+	var variants []domain.MediaVariant
+	if len(m.Variants) > 0 {
+		_ = json.Unmarshal(m.Variants, &variants)
+	}
+	srcset := make([]string, 0, len(variants))
+	for _, v := range variants {
+		srcset = append(srcset, fmt.Sprintf("%s %dw", v.URL, v.Width))
+		if v.Name == "medium" {
+			p.Src = v.URL
+		}
+	}
+	p.SrcSet = strings.Join(srcset, ", ")
+	return p
+}
+
+var (
+	colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|[a-zA-Z]{3,20}|(rgb|rgba|hsl|hsla)\([0-9.,%\s]+\))$`)
+	fontPattern  = regexp.MustCompile(`^[A-Za-z0-9 \-]{1,60}$`)
+)
+
+// themeStyle turns the event theme into CSS custom properties. Values
+// that do not look like a color, font name or http(s) URL are dropped so
+// they cannot break out of the declaration.
+func themeStyle(theme *domain.EventTheme) Style {
+	vars := map[string]string{
+		"--primary":   "#8b5e3c",
+		"--secondary": "#f7f1ea",
+		"--font":      "Georgia, serif",
+	}
+	var style Style
+	if theme == nil {
+		style.Vars = cssVars(vars)
+		return style
+	}
+
+	if c := deref(theme.PrimaryColor); colorPattern.MatchString(c) {
+		vars["--primary"] = c
+	}
+	if c := deref(theme.SecondaryColor); colorPattern.MatchString(c) {
+		vars["--secondary"] = c
+	}
+	if f := strings.TrimSpace(deref(theme.FontFamily)); fontPattern.MatchString(f) {
+		vars["--font"] = fmt.Sprintf("'%s', Georgia, serif", f)
+		style.FontURL = "https://fonts.googleapis.com/css2?family=" + url.QueryEscape(f) + "&display=swap"
+	}
+	if u := deref(theme.BackgroundURL); isSafeURL(u) {
+		vars["--background"] = fmt.Sprintf("url('%s')", u)
+	}
+	style.Vars = cssVars(vars)
+
+	// Custom CSS is written by the event owner; only keep it from closing
+	// the <style> element
+	if css := strings.TrimSpace(deref(theme.CustomCSS)); css != "" {
+		style.Custom = template.CSS(strings.ReplaceAll(css, "<", `\3c `))
+		style.HasCustom = true
+	}
+	return style
+}
+
+func cssVars(vars map[string]string) template.CSS {
+	var b strings.Builder
+	for _, name := range []string{"--primary", "--secondary", "--font", "--background"} {
+		if v, ok := vars[name]; ok {
+			fmt.Fprintf(&b, "%s: %s; ", name, v)
+		}
+	}
+	return template.CSS(b.String())
+}
+
+func isSafeURL(raw string) bool {
+	if raw == "" || strings.ContainsAny(raw, "'\"\\()<> \n\r\t") {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" || u.Scheme == "http" || (u.Scheme == "" && strings.HasPrefix(raw, "/"))
+}
+
+// contentText reads a string field of section content (or of an object
+// nested in it). Content saved before it was validated may not have the
+// expected shape, so mismatches read as empty.
+func contentText(data interface{}, key string) string {
+	obj, _ := data.(map[string]interface{})
+	if v, ok := obj[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// contentItems reads a list of objects from section content.
+func contentItems(data interface{}, key string) []map[string]interface{} {
+	obj, _ := data.(map[string]interface{})
+	list, _ := obj[key].([]interface{})
+	items := make([]map[string]interface{}, 0, len(list))
+	for _, v := range list {
+		if item, ok := v.(map[string]interface{}); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
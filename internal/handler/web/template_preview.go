@@ -0,0 +1,43 @@
+package web
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+// RenderTemplatePreview renders a template's sections with their default
+// content on a sample event, as a new event would first look.
+func RenderTemplatePreview(tmpl *domain.Template) ([]byte, error) {
+	venue := "Venue"
+	address := "Venue address"
+	date := time.Now().AddDate(0, 1, 0).Truncate(24 * time.Hour).Add(19 * time.Hour)
+
+	sections := make([]domain.EventSection, 0, len(tmpl.Sections))
+	for _, ts := range tmpl.Sections {
+		sections = append(sections, domain.EventSection{
+			ID:                uuid.New(),
+			TemplateSectionID: ts.ID,
+			Type:              ts.Type,
+			Content:           ts.DefaultContent,
+			IsVisible:         true,
+			SortOrder:         ts.SortOrder,
+		})
+	}
+
+	resp := &domain.PublicEventResponse{
+		Event: &domain.Event{
+			TemplateID:      tmpl.ID,
+			Title:           tmpl.Name,
+			Slug:            "preview",
+			EventDate:       date,
+			LocationName:    &venue,
+			LocationAddress: &address,
+			MaxPartySize:    2,
+			GuestUploads:    domain.GuestUploadsOff,
+		},
+		Sections: sections,
+	}
+	return renderPage(resp, nil, Meta{Title: tmpl.Name})
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimiter counts hits per key in fixed time windows.
+type RateLimiter interface {
+	// Allow records a hit and reports whether key is still within limit
+	// for the current window.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
+
+type redisRateLimiter struct {
+	rdb *redis.Client
+}
+
+// NewRedisRateLimiter shares counters between all API instances.
+func NewRedisRateLimiter(rdb *redis.Client) RateLimiter {
+	return &redisRateLimiter{rdb: rdb}
+}
+
+func (l *redisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	key = "ratelimit:" + key
+	count, err := l.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("rate limiter: %w", err)
+	}
+	// The first hit opens the window
+	if count == 1 {
+		if err := l.rdb.Expire(ctx, key, window).Err(); err != nil {
+			return false, fmt.Errorf("rate limiter: %w", err)
+		}
+	}
+	return count <= int64(limit), nil
+}
+
+type memoryRateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*memoryWindow
+}
+
+type memoryWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// NewMemoryRateLimiter keeps counters in process, for when Redis is not
+// available.
+func NewMemoryRateLimiter() RateLimiter {
+	return &memoryRateLimiter{windows: make(map[string]*memoryWindow)}
+}
+
+func (l *memoryRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	w, ok := l.windows[key]
+	if !ok || now.After(w.resetAt) {
+		// Drop expired windows now and then so the map does not grow
+		if len(l.windows) > 10000 {
+			for k, old := range l.windows {
+				if now.After(old.resetAt) {
+					delete(l.windows, k)
+				}
+			}
+		}
+		w = &memoryWindow{resetAt: now.Add(window)}
+		l.windows[key] = w
+	}
+	w.count++
+	return w.count <= limit, nil
+}
@@ -0,0 +1,169 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Social preview size recommended for Open Graph and Twitter cards.
+const (
+	CardWidth  = 1200
+	CardHeight = 630
+)
+
+// Card is a social preview image: a title and a few detail lines over the
+// theme color or a darkened background photo.
+type Card struct {
+	Title      string
+	Lines      []string
+	Primary    color.Color
+	Secondary  color.Color
+	Background image.Image // optional
+}
+
+const (
+	cardMargin    = 80
+	maxTitleLines = 3
+)
+
+var (
+	titleFont  = mustParseFont(gobold.TTF)
+	detailFont = mustParseFont(goregular.TTF)
+)
+
+// RenderCard draws c at CardWidth x CardHeight.
+func RenderCard(c Card) (image.Image, error) {
+	titleFace, err := opentype.NewFace(titleFont, &opentype.FaceOptions{Size: 68, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("imaging: %w", err)
+	}
+	defer titleFace.Close()
+	detailFace, err := opentype.NewFace(detailFont, &opentype.FaceOptions{Size: 34, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("imaging: %w", err)
+	}
+	defer detailFace.Close()
+
+	dst := image.NewRGBA(image.Rect(0, 0, CardWidth, CardHeight))
+	titleColor, detailColor := c.Primary, color.Color(color.RGBA{0x55, 0x55, 0x55, 0xff})
+	if c.Background != nil {
+		fillCrop(dst, c.Background)
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.RGBA{0, 0, 0, 0x8c}), image.Point{}, draw.Over)
+		titleColor, detailColor = color.White, color.RGBA{0xee, 0xee, 0xee, 0xff}
+	} else {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(c.Secondary), image.Point{}, draw.Src)
+	}
+
+	// Frame in the theme's primary color
+	frame := image.Rect(32, 32, CardWidth-32, CardHeight-32)
+	for _, r := range []image.Rectangle{
+		image.Rect(frame.Min.X, frame.Min.Y, frame.Max.X, frame.Min.Y+6),
+		image.Rect(frame.Min.X, frame.Max.Y-6, frame.Max.X, frame.Max.Y),
+		image.Rect(frame.Min.X, frame.Min.Y, frame.Min.X+6, frame.Max.Y),
+		image.Rect(frame.Max.X-6, frame.Min.Y, frame.Max.X, frame.Max.Y),
+	} {
+		draw.Draw(dst, r, image.NewUniform(c.Primary), image.Point{}, draw.Src)
+	}
+
+	title := wrap(titleFace, c.Title, CardWidth-2*cardMargin)
+	if len(title) > maxTitleLines {
+		title = title[:maxTitleLines]
+		title[maxTitleLines-1] = strings.TrimSpace(title[maxTitleLines-1]) + "…"
+	}
+
+	titleHeight := titleFace.Metrics().Height.Ceil()
+	detailHeight := detailFace.Metrics().Height.Ceil()
+	total := len(title)*titleHeight + 24 + len(c.Lines)*detailHeight
+	y := (CardHeight-total)/2 + titleFace.Metrics().Ascent.Ceil()
+	for _, line := range title {
+		drawCentered(dst, titleFace, titleColor, line, y)
+		y += titleHeight
+	}
+	y += 24
+	for _, line := range c.Lines {
+		drawCentered(dst, detailFace, detailColor, line, y)
+		y += detailHeight
+	}
+	return dst, nil
+}
+
+// ParseHexColor parses "#rgb" or "#rrggbb".
+func ParseHexColor(s string) (color.Color, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return nil, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, true
+}
+
+// fillCrop scales img to cover dst, cropping the overflow around the
+// center.
+func fillCrop(dst *image.RGBA, img image.Image) {
+	b := img.Bounds()
+	db := dst.Bounds()
+	src := b
+	if b.Dx()*db.Dy() > b.Dy()*db.Dx() {
+		w := b.Dy() * db.Dx() / db.Dy()
+		src.Min.X = b.Min.X + (b.Dx()-w)/2
+		src.Max.X = src.Min.X + w
+	} else {
+		h := b.Dx() * db.Dy() / db.Dx()
+		src.Min.Y = b.Min.Y + (b.Dy()-h)/2
+		src.Max.Y = src.Min.Y + h
+	}
+	draw.Draw(dst, db, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, db, img, src, draw.Over, nil)
+}
+
+// wrap breaks text into lines no wider than width pixels.
+func wrap(face font.Face, text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		next := word
+		if line != "" {
+			next = line + " " + word
+		}
+		if line != "" && font.MeasureString(face, next).Ceil() > width {
+			lines = append(lines, line)
+			next = word
+		}
+		line = next
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func drawCentered(dst *image.RGBA, face font.Face, c color.Color, text string, y int) {
+	d := &font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: face}
+	x := (CardWidth - d.MeasureString(text).Ceil()) / 2
+	d.Dot = fixed.P(x, y)
+	d.DrawString(text)
+}
+
+func mustParseFont(ttf []byte) *opentype.Font {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,158 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// JPEG markers
+const (
+	markerSOS  = 0xDA
+	markerEOI  = 0xD9
+	markerAPP1 = 0xE1 // EXIF (incl. GPS) and XMP
+	markerAPPD = 0xED // IPTC / Photoshop
+	markerCOM  = 0xFE
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngMetadataChunks are the PNG chunks dropped by StripMetadata.
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true,
+}
+
+// Orientation returns the EXIF orientation of a JPEG, or 1 when it has
+// none.
+func Orientation(data []byte) int {
+	orientation := 1
+	walkJPEG(data, func(marker byte, payload []byte) {
+		if marker != markerAPP1 || !bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+			return
+		}
+		if o := exifOrientation(payload[6:]); o > 0 {
+			orientation = o
+		}
+	})
+	return orientation
+}
+
+// StripMetadata removes EXIF (including GPS), XMP, IPTC and comments from
+// a JPEG, and text/EXIF chunks from a PNG, without re-encoding the
+// pixels. It reports false when nothing was removed or the format is not
+// supported.
+func StripMetadata(data []byte) ([]byte, bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		return stripJPEG(data)
+	case bytes.HasPrefix(data, pngSignature):
+		return stripPNG(data)
+	}
+	return data, false
+}
+
+// walkJPEG calls fn for every marker segment before the image data and
+// returns the offset of the SOS segment (or len(data) if not found).
+func walkJPEG(data []byte, fn func(marker byte, payload []byte)) int {
+	if !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		return len(data)
+	}
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return len(data)
+		}
+		marker := data[i+1]
+		if marker == 0xFF { // fill byte
+			i++
+			continue
+		}
+		if marker == markerSOS || marker == markerEOI {
+			return i
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			i += 2
+			continue
+		}
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+		if end > len(data) || end < i+4 {
+			return len(data)
+		}
+		fn(marker, data[i+4:end])
+		i = end
+	}
+	return len(data)
+}
+
+func stripJPEG(data []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	stripped := false
+	sos := walkJPEG(data, func(marker byte, payload []byte) {
+		if marker == markerAPP1 || marker == markerAPPD || marker == markerCOM {
+			stripped = true
+			return
+		}
+		out = append(out, 0xFF, marker)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(payload)+2))
+		out = append(out, payload...)
+	})
+	if !stripped || sos >= len(data) {
+		return data, false
+	}
+	return append(out, data[sos:]...), true
+}
+
+func stripPNG(data []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	stripped := false
+	for i := len(pngSignature); i+12 <= len(data); {
+		end := i + 12 + int(binary.BigEndian.Uint32(data[i:]))
+		if end > len(data) || end < i+12 {
+			return data, false
+		}
+		if pngMetadataChunks[string(data[i+4:i+8])] {
+			stripped = true
+		} else {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	if !stripped {
+		return data, false
+	}
+	return out, true
+}
+
+// exifOrientation reads tag 0x0112 from IFD0 of a TIFF-structured EXIF
+// block.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < count; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
@@ -1,21 +1,194 @@
 package storage
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/galihaleanda/event-invitation/internal/config"
 )
 
-func EnsureStorageDirs(cfg *config.Config) error {
+// Local keeps files on disk under BasePath; they are served publicly by
+// the /uploads static route. Direct uploads are PUT to the same URL with a
+// signature from PresignedPutURL.
+type Local struct {
+	basePath     string
+	baseURL      string
+	uploadSecret string
+}
+
+func NewLocal(cfg config.StorageConfig) (*Local, error) {
 	dirs := []string{
-		cfg.Storage.BasePath,
-		fmt.Sprintf("%s/events", cfg.Storage.BasePath),
+		cfg.BasePath,
+		fmt.Sprintf("%s/events", cfg.BasePath),
 	}
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+			return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 	}
+	return &Local{
+		basePath:     cfg.BasePath,
+		baseURL:      strings.TrimSuffix(cfg.BaseURL, "/"),
+		uploadSecret: cfg.UploadSecret,
+	}, nil
+}
+
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	return l.write(key, r, os.O_TRUNC)
+}
+
+// Create is Put for a key that must not exist yet. It fails with
+// ErrExists instead of overwriting, even when two uploads race.
+func (l *Local) Create(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	return l.write(key, r, os.O_EXCL)
+}
+
+func (l *Local) write(key string, r io.Reader, flag int) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("local storage: %w", err)
+	}
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|flag, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return ErrExists
+	}
+	if err != nil {
+		return fmt.Errorf("local storage: %w", err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(path)
+		return fmt.Errorf("local storage: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("local storage: %w", err)
+	}
 	return nil
 }
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	return f, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("local storage: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) URL(key string) string {
+	return l.baseURL + "/" + key
+}
+
+// PresignedGetURL returns the public URL; files on disk are not private.
+func (l *Local) PresignedGetURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return l.URL(key), nil
+}
+
+// PresignedPutURL returns the public URL of the key signed with its expiry
+// and size; the API accepts a PUT to it after VerifyUpload.
+func (l *Local) PresignedPutURL(ctx context.Context, key string, size int64, expiry time.Duration) (string, http.Header, error) {
+	if _, err := l.path(key); err != nil {
+		return "", nil, err
+	}
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	sizeStr := strconv.FormatInt(size, 10)
+
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("size", sizeStr)
+	q.Set("signature", l.sign(key, expires, sizeStr))
+	return l.URL(key) + "?" + q.Encode(), nil, nil
+}
+
+// VerifyUpload checks the query of a URL from PresignedPutURL and returns
+// the maximum number of bytes the upload may write.
+func (l *Local) VerifyUpload(key string, query url.Values) (int64, error) {
+	expires, sizeStr, signature := query.Get("expires"), query.Get("size"), query.Get("signature")
+	if !hmac.Equal([]byte(signature), []byte(l.sign(key, expires, sizeStr))) {
+		return 0, fmt.Errorf("local storage: invalid upload signature")
+	}
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return 0, fmt.Errorf("local storage: upload url has expired")
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("local storage: invalid upload size")
+	}
+	return size, nil
+}
+
+func (l *Local) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	head := make([]byte, SniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	return &ObjectInfo{Size: fi.Size(), ContentType: SniffContentType(head[:n])}, nil
+}
+
+func (l *Local) sign(key, expires, size string) string {
+	mac := hmac.New(sha256.New, []byte(l.uploadSecret))
+	mac.Write([]byte("upload:" + key + "\n" + expires + "\n" + size))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path resolves a key below basePath, rejecting keys that escape it.
+func (l *Local) path(key string) (string, error) {
+	path := filepath.Join(l.basePath, filepath.FromSlash(key))
+	rel, err := filepath.Rel(l.basePath, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("local storage: invalid key %q", key)
+	}
+	return path, nil
+}
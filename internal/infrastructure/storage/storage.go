@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/galihaleanda/event-invitation/internal/config"
+)
+
+// ErrNotFound is returned by Get and Stat when no object exists under the
+// key.
+var ErrNotFound = errors.New("storage: object not found")
+
+// ErrExists is returned by Local.Create when the key is already taken.
+var ErrExists = errors.New("storage: object already exists")
+
+// ObjectInfo describes a stored object. ContentType is sniffed from the
+// object's first bytes rather than trusted from the uploader.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// Storage stores uploaded files under slash-separated keys such as
+// "events/<event_id>/<file>".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens an object for reading; the caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL is the public URL of an object.
+	URL(key string) string
+	// PresignedGetURL is a time-limited download URL, usable even when the
+	// bucket is private.
+	PresignedGetURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// PresignedPutURL lets a client upload an object of the given size
+	// directly with an HTTP PUT, bypassing the API process. The PUT must
+	// carry the returned headers, and fails once the object exists.
+	PresignedPutURL(ctx context.Context, key string, size int64, expiry time.Duration) (string, http.Header, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+}
+
+// New returns the backend selected by STORAGE_DRIVER.
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "", "local":
+		return NewLocal(cfg.Storage)
+	case "s3":
+		return NewS3(cfg.Storage)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+// SniffLen is how many leading bytes SniffContentType looks at.
+const SniffLen = 512
+
+// SniffContentType detects the content type of a file from its first
+// bytes. QuickTime (.mov) files are not known to http.DetectContentType
+// and are recognized by their "ftyp" box.
+func SniffContentType(head []byte) string {
+	contentType := http.DetectContentType(head)
+	if contentType == "application/octet-stream" && len(head) >= 12 && bytes.Equal(head[4:8], []byte("ftyp")) {
+		return "video/quicktime"
+	}
+	return contentType
+}
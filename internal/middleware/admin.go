@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+// AdminOnly lets through users with the admin role. It runs after
+// AuthMiddleware and reads the role from the database, so revoking it
+// takes effect without waiting for the token to expire.
+func AdminOnly(users domain.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get(UserIDKey)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"success": false, "error": "authorization header required"})
+			return
+		}
+
+		user, err := users.FindByID(c.Request.Context(), userID.(uuid.UUID))
+		if err != nil || user == nil || user.Role != domain.UserRoleAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"success": false, "error": "admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/cache"
+)
+
+// RateLimit allows each client IP limit requests per window on the routes
+// it guards. If the limiter fails, requests are let through.
+func RateLimit(limiter cache.RateLimiter, name string, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, err := limiter.Allow(c.Request.Context(), name+":"+c.ClientIP(), limit, window)
+		if err != nil {
+			log.Printf("rate limit %s: %v", name, err)
+		} else if !allowed {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"success": false, "error": "too many requests, please try again later"})
+			return
+		}
+		c.Next()
+	}
+}
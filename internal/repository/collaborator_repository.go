@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+type collaboratorRepository struct {
+	db *sqlx.DB
+}
+
+func NewCollaboratorRepository(db *sqlx.DB) domain.CollaboratorRepository {
+	return &collaboratorRepository{db: db}
+}
+
+func (r *collaboratorRepository) Create(ctx context.Context, collaborator *domain.EventCollaborator) error {
+	query := `
+		INSERT INTO event_collaborators (id, event_id, email, user_id, role, invited_by, accepted_at, created_at)
+		VALUES (:id, :event_id, :email, :user_id, :role, :invited_by, :accepted_at, :created_at)
+	`
+	_, err := r.db.NamedExecContext(ctx, query, collaborator)
+	if err != nil {
+		return fmt.Errorf("collaboratorRepository.Create: %w", err)
+	}
+	return nil
+}
+
+func (r *collaboratorRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.EventCollaborator, error) {
+	var collaborator domain.EventCollaborator
+	query := `SELECT * FROM event_collaborators WHERE id = $1`
+	if err := r.db.GetContext(ctx, &collaborator, query, id); err != nil {
+		return nil, fmt.Errorf("collaboratorRepository.FindByID: %w", err)
+	}
+	return &collaborator, nil
+}
+
+func (r *collaboratorRepository) FindByEventID(ctx context.Context, eventID uuid.UUID) ([]domain.EventCollaborator, error) {
+	var collaborators []domain.EventCollaborator
+	query := `SELECT * FROM event_collaborators WHERE event_id = $1 ORDER BY created_at ASC`
+	if err := r.db.SelectContext(ctx, &collaborators, query, eventID); err != nil {
+		return nil, fmt.Errorf("collaboratorRepository.FindByEventID: %w", err)
+	}
+	return collaborators, nil
+}
+
+func (r *collaboratorRepository) FindMember(ctx context.Context, eventID, userID uuid.UUID) (*domain.EventCollaborator, error) {
+	var collaborator domain.EventCollaborator
+	query := `
+		SELECT * FROM event_collaborators
+		WHERE event_id = $1 AND user_id = $2 AND accepted_at IS NOT NULL
+	`
+	if err := r.db.GetContext(ctx, &collaborator, query, eventID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("collaboratorRepository.FindMember: %w", err)
+	}
+	return &collaborator, nil
+}
+
+func (r *collaboratorRepository) FindPendingByEmail(ctx context.Context, email string) ([]domain.EventCollaborator, error) {
+	var collaborators []domain.EventCollaborator
+	query := `
+		SELECT c.*, e.title as event_title
+		FROM event_collaborators c
+		JOIN events e ON e.id = c.event_id
+		WHERE LOWER(c.email) = LOWER($1) AND c.accepted_at IS NULL
+		ORDER BY c.created_at DESC
+	`
+	if err := r.db.SelectContext(ctx, &collaborators, query, email); err != nil {
+		return nil, fmt.Errorf("collaboratorRepository.FindPendingByEmail: %w", err)
+	}
+	return collaborators, nil
+}
+
+func (r *collaboratorRepository) EmailExists(ctx context.Context, eventID uuid.UUID, email string) (bool, error) {
+	var count int
+	query := `SELECT COUNT(1) FROM event_collaborators WHERE event_id = $1 AND LOWER(email) = LOWER($2)`
+	if err := r.db.GetContext(ctx, &count, query, eventID, email); err != nil {
+		return false, fmt.Errorf("collaboratorRepository.EmailExists: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *collaboratorRepository) UpdateRole(ctx context.Context, id uuid.UUID, role domain.CollaboratorRole) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE event_collaborators SET role = $1 WHERE id = $2`, role, id)
+	if err != nil {
+		return fmt.Errorf("collaboratorRepository.UpdateRole: %w", err)
+	}
+	return nil
+}
+
+func (r *collaboratorRepository) Accept(ctx context.Context, id, userID uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE event_collaborators SET user_id = $1, accepted_at = $2 WHERE id = $3`,
+		userID, at, id,
+	)
+	if err != nil {
+		return fmt.Errorf("collaboratorRepository.Accept: %w", err)
+	}
+	return nil
+}
+
+func (r *collaboratorRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM event_collaborators WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("collaboratorRepository.Delete: %w", err)
+	}
+	return nil
+}
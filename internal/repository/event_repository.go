@@ -21,8 +21,8 @@ func NewEventRepository(db *sqlx.DB) domain.EventRepository {
 
 func (r *eventRepository) Create(ctx context.Context, event *domain.Event) error {
 	query := `
-		INSERT INTO events (id, user_id, template_id, title, slug, event_date, location_name, location_address, is_published, view_count, created_at, updated_at)
-		VALUES (:id, :user_id, :template_id, :title, :slug, :event_date, :location_name, :location_address, :is_published, :view_count, :created_at, :updated_at)
+		INSERT INTO events (id, user_id, template_id, template_version, title, slug, event_date, location_name, location_address, max_party_size, rsvp_deadline, max_attendees, waitlist_enabled, wishes_auto_approve, guest_uploads, is_published, view_count, created_at, updated_at)
+		VALUES (:id, :user_id, :template_id, :template_version, :title, :slug, :event_date, :location_name, :location_address, :max_party_size, :rsvp_deadline, :max_attendees, :waitlist_enabled, :wishes_auto_approve, :guest_uploads, :is_published, :view_count, :created_at, :updated_at)
 	`
 	_, err := r.db.NamedExecContext(ctx, query, event)
 	if err != nil {
@@ -61,6 +61,22 @@ func (r *eventRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([
 	return events, nil
 }
 
+func (r *eventRepository) FindByMember(ctx context.Context, userID uuid.UUID) ([]domain.Event, error) {
+	var events []domain.Event
+	query := `
+		SELECT e.*, 'owner' as role FROM events e WHERE e.user_id = $1
+		UNION ALL
+		SELECT e.*, c.role FROM events e
+		JOIN event_collaborators c ON c.event_id = e.id
+		WHERE c.user_id = $1 AND c.accepted_at IS NOT NULL AND e.user_id != $1
+		ORDER BY created_at DESC
+	`
+	if err := r.db.SelectContext(ctx, &events, query, userID); err != nil {
+		return nil, fmt.Errorf("eventRepository.FindByMember: %w", err)
+	}
+	return events, nil
+}
+
 func (r *eventRepository) Update(ctx context.Context, event *domain.Event) error {
 	query := `
 		UPDATE events SET
@@ -68,6 +84,12 @@ func (r *eventRepository) Update(ctx context.Context, event *domain.Event) error
 			event_date = :event_date,
 			location_name = :location_name,
 			location_address = :location_address,
+			max_party_size = :max_party_size,
+			rsvp_deadline = :rsvp_deadline,
+			max_attendees = :max_attendees,
+			waitlist_enabled = :waitlist_enabled,
+			wishes_auto_approve = :wishes_auto_approve,
+			guest_uploads = :guest_uploads,
 			is_published = :is_published,
 			updated_at = :updated_at
 		WHERE id = :id AND user_id = :user_id
@@ -137,6 +159,47 @@ func (r *eventRepository) FindThemeByEventID(ctx context.Context, eventID uuid.U
 	return &theme, nil
 }
 
+// Background music
+
+func (r *eventRepository) UpsertMusic(ctx context.Context, music *domain.EventMusic) error {
+	query := `
+		INSERT INTO event_music (event_id, media_id, autoplay, loop, start_seconds, updated_at)
+		VALUES (:event_id, :media_id, :autoplay, :loop, :start_seconds, :updated_at)
+		ON CONFLICT (event_id) DO UPDATE SET
+			media_id = EXCLUDED.media_id,
+			autoplay = EXCLUDED.autoplay,
+			loop = EXCLUDED.loop,
+			start_seconds = EXCLUDED.start_seconds,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.db.NamedExecContext(ctx, query, music)
+	if err != nil {
+		return fmt.Errorf("eventRepository.UpsertMusic: %w", err)
+	}
+	return nil
+}
+
+// FindMusicByEventID returns nil when the event has no music.
+func (r *eventRepository) FindMusicByEventID(ctx context.Context, eventID uuid.UUID) (*domain.EventMusic, error) {
+	var music domain.EventMusic
+	query := `SELECT * FROM event_music WHERE event_id = $1`
+	if err := r.db.GetContext(ctx, &music, query, eventID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("eventRepository.FindMusicByEventID: %w", err)
+	}
+	return &music, nil
+}
+
+func (r *eventRepository) DeleteMusic(ctx context.Context, eventID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM event_music WHERE event_id = $1`, eventID)
+	if err != nil {
+		return fmt.Errorf("eventRepository.DeleteMusic: %w", err)
+	}
+	return nil
+}
+
 // Sections
 
 func (r *eventRepository) CreateSections(ctx context.Context, sections []domain.EventSection) error {
@@ -144,8 +207,8 @@ func (r *eventRepository) CreateSections(ctx context.Context, sections []domain.
 		return nil
 	}
 	query := `
-		INSERT INTO event_sections (id, event_id, template_section_id, content, is_visible, sort_order)
-		VALUES (:id, :event_id, :template_section_id, :content, :is_visible, :sort_order)
+		INSERT INTO event_sections (id, event_id, template_section_id, content, is_visible, sort_order, visible_groups, visible_guest_ids)
+		VALUES (:id, :event_id, :template_section_id, :content, :is_visible, :sort_order, :visible_groups, :visible_guest_ids)
 	`
 	_, err := r.db.NamedExecContext(ctx, query, sections)
 	if err != nil {
@@ -156,7 +219,12 @@ func (r *eventRepository) CreateSections(ctx context.Context, sections []domain.
 
 func (r *eventRepository) FindSectionsByEventID(ctx context.Context, eventID uuid.UUID) ([]domain.EventSection, error) {
 	var sections []domain.EventSection
-	query := `SELECT * FROM event_sections WHERE event_id = $1 ORDER BY sort_order ASC`
+	query := `
+		SELECT es.*, ts.type
+		FROM event_sections es
+		JOIN template_sections ts ON ts.id = es.template_section_id
+		WHERE es.event_id = $1
+		ORDER BY es.sort_order ASC`
 	if err := r.db.SelectContext(ctx, &sections, query, eventID); err != nil {
 		return nil, fmt.Errorf("eventRepository.FindSectionsByEventID: %w", err)
 	}
@@ -168,7 +236,9 @@ func (r *eventRepository) UpdateSection(ctx context.Context, section *domain.Eve
 		UPDATE event_sections SET
 			content = :content,
 			is_visible = :is_visible,
-			sort_order = :sort_order
+			sort_order = :sort_order,
+			visible_groups = :visible_groups,
+			visible_guest_ids = :visible_guest_ids
 		WHERE id = :id AND event_id = :event_id
 	`
 	_, err := r.db.NamedExecContext(ctx, query, section)
@@ -178,6 +248,180 @@ func (r *eventRepository) UpdateSection(ctx context.Context, section *domain.Eve
 	return nil
 }
 
+// ApplyTemplateUpgrade moves the event to upgrade.ToVersion, adding and
+// updating its sections. The version check guards against applying the
+// same upgrade twice.
+func (r *eventRepository) ApplyTemplateUpgrade(ctx context.Context, eventID uuid.UUID, upgrade *domain.TemplateUpgrade) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("eventRepository.ApplyTemplateUpgrade: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE events SET template_version = $1, updated_at = NOW() WHERE id = $2 AND template_version = $3`,
+		upgrade.ToVersion, eventID, upgrade.FromVersion,
+	)
+	if err != nil {
+		return false, fmt.Errorf("eventRepository.ApplyTemplateUpgrade: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("eventRepository.ApplyTemplateUpgrade: %w", err)
+	}
+	if n == 0 {
+		return false, nil
+	}
+
+	if len(upgrade.Added) > 0 {
+		query := `
+			INSERT INTO event_sections (id, event_id, template_section_id, content, is_visible, sort_order, visible_groups, visible_guest_ids)
+			VALUES (:id, :event_id, :template_section_id, :content, :is_visible, :sort_order, :visible_groups, :visible_guest_ids)
+		`
+		if _, err := tx.NamedExecContext(ctx, query, upgrade.Added); err != nil {
+			return false, fmt.Errorf("eventRepository.ApplyTemplateUpgrade: %w", err)
+		}
+	}
+	// Sections edited since the plan was made would lose those edits
+	for _, section := range upgrade.Updated {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE event_sections SET content = $1 WHERE id = $2 AND event_id = $3 AND content = $4`,
+			section.Content, section.ID, eventID, upgrade.Original[section.ID],
+		)
+		if err != nil {
+			return false, fmt.Errorf("eventRepository.ApplyTemplateUpgrade: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return false, fmt.Errorf("eventRepository.ApplyTemplateUpgrade: %w", err)
+		}
+		if n == 0 {
+			return false, nil
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("eventRepository.ApplyTemplateUpgrade: %w", err)
+	}
+	return true, nil
+}
+
+// RSVP questions
+
+func (r *eventRepository) CreateQuestion(ctx context.Context, question *domain.EventQuestion) error {
+	query := `
+		INSERT INTO event_questions (id, event_id, label, type, options, is_required, sort_order, created_at)
+		VALUES (:id, :event_id, :label, :type, :options, :is_required, :sort_order, :created_at)
+	`
+	_, err := r.db.NamedExecContext(ctx, query, question)
+	if err != nil {
+		return fmt.Errorf("eventRepository.CreateQuestion: %w", err)
+	}
+	return nil
+}
+
+func (r *eventRepository) FindQuestionsByEventID(ctx context.Context, eventID uuid.UUID) ([]domain.EventQuestion, error) {
+	var questions []domain.EventQuestion
+	query := `SELECT * FROM event_questions WHERE event_id = $1 ORDER BY sort_order ASC, created_at ASC`
+	if err := r.db.SelectContext(ctx, &questions, query, eventID); err != nil {
+		return nil, fmt.Errorf("eventRepository.FindQuestionsByEventID: %w", err)
+	}
+	return questions, nil
+}
+
+func (r *eventRepository) UpdateQuestion(ctx context.Context, question *domain.EventQuestion) error {
+	query := `
+		UPDATE event_questions SET
+			label = :label,
+			options = :options,
+			is_required = :is_required,
+			sort_order = :sort_order
+		WHERE id = :id AND event_id = :event_id
+	`
+	_, err := r.db.NamedExecContext(ctx, query, question)
+	if err != nil {
+		return fmt.Errorf("eventRepository.UpdateQuestion: %w", err)
+	}
+	return nil
+}
+
+func (r *eventRepository) DeleteQuestion(ctx context.Context, eventID, questionID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM event_questions WHERE id = $1 AND event_id = $2`, questionID, eventID)
+	if err != nil {
+		return fmt.Errorf("eventRepository.DeleteQuestion: %w", err)
+	}
+	return nil
+}
+
+// Sessions
+
+func (r *eventRepository) CreateSession(ctx context.Context, session *domain.EventSession) error {
+	query := `
+		INSERT INTO event_sessions (id, event_id, name, starts_at, ends_at, venue_name, venue_address, map_url, sort_order, visible_groups, visible_guest_ids, created_at)
+		VALUES (:id, :event_id, :name, :starts_at, :ends_at, :venue_name, :venue_address, :map_url, :sort_order, :visible_groups, :visible_guest_ids, :created_at)
+	`
+	_, err := r.db.NamedExecContext(ctx, query, session)
+	if err != nil {
+		return fmt.Errorf("eventRepository.CreateSession: %w", err)
+	}
+	return nil
+}
+
+func (r *eventRepository) FindSessionsByEventID(ctx context.Context, eventID uuid.UUID) ([]domain.EventSession, error) {
+	var sessions []domain.EventSession
+	query := `SELECT * FROM event_sessions WHERE event_id = $1 ORDER BY sort_order ASC, starts_at ASC`
+	if err := r.db.SelectContext(ctx, &sessions, query, eventID); err != nil {
+		return nil, fmt.Errorf("eventRepository.FindSessionsByEventID: %w", err)
+	}
+	return sessions, nil
+}
+
+func (r *eventRepository) UpdateSession(ctx context.Context, session *domain.EventSession) error {
+	query := `
+		UPDATE event_sessions SET
+			name = :name,
+			starts_at = :starts_at,
+			ends_at = :ends_at,
+			venue_name = :venue_name,
+			venue_address = :venue_address,
+			map_url = :map_url,
+			sort_order = :sort_order,
+			visible_groups = :visible_groups,
+			visible_guest_ids = :visible_guest_ids
+		WHERE id = :id AND event_id = :event_id
+	`
+	_, err := r.db.NamedExecContext(ctx, query, session)
+	if err != nil {
+		return fmt.Errorf("eventRepository.UpdateSession: %w", err)
+	}
+	return nil
+}
+
+// DeleteSession removes the session and drops it from every guest's choice.
+func (r *eventRepository) DeleteSession(ctx context.Context, eventID, sessionID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("eventRepository.DeleteSession: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_sessions WHERE id = $1 AND event_id = $2`, sessionID, eventID); err != nil {
+		return fmt.Errorf("eventRepository.DeleteSession: %w", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`UPDATE guests SET session_ids = array_remove(session_ids, $1) WHERE event_id = $2`,
+		sessionID.String(), eventID,
+	)
+	if err != nil {
+		return fmt.Errorf("eventRepository.DeleteSession: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("eventRepository.DeleteSession: %w", err)
+	}
+	return nil
+}
+
 // Stats
 
 func (r *eventRepository) GetStats(ctx context.Context, eventID uuid.UUID) (*domain.EventStats, error) {
@@ -186,9 +430,13 @@ func (r *eventRepository) GetStats(ctx context.Context, eventID uuid.UUID) (*dom
 		SELECT
 			COUNT(*) as total_rsvp,
 			COUNT(*) FILTER (WHERE rsvp_status = 'yes') as total_attending,
+			COALESCE(SUM(attendees) FILTER (WHERE rsvp_status = 'yes'), 0) as total_headcount,
 			COUNT(*) FILTER (WHERE rsvp_status = 'no') as total_declined,
 			COUNT(*) FILTER (WHERE rsvp_status = 'pending') as total_pending,
-			COUNT(*) FILTER (WHERE message IS NOT NULL AND message != '') as total_messages
+			COUNT(*) FILTER (WHERE rsvp_status = 'waitlist') as total_waitlist,
+			COUNT(*) FILTER (WHERE message IS NOT NULL AND message != '') as total_messages,
+			COUNT(*) FILTER (WHERE checked_in_at IS NOT NULL) as total_checked_in,
+			COALESCE(SUM(attendees) FILTER (WHERE checked_in_at IS NOT NULL), 0) as checked_in_headcount
 		FROM guests
 		WHERE event_id = $1
 	`
@@ -197,3 +445,26 @@ func (r *eventRepository) GetStats(ctx context.Context, eventID uuid.UUID) (*dom
 	}
 	return &stats, nil
 }
+
+func (r *eventRepository) GetSessionStats(ctx context.Context, eventID uuid.UUID) ([]domain.SessionStats, error) {
+	var stats []domain.SessionStats
+	query := `
+		SELECT
+			s.id as session_id,
+			s.name,
+			COUNT(g.id) as total_attending,
+			COALESCE(SUM(g.attendees), 0) as total_headcount
+		FROM event_sessions s
+		LEFT JOIN guests g
+			ON g.event_id = s.event_id
+			AND g.rsvp_status = 'yes'
+			AND s.id::text = ANY(g.session_ids)
+		WHERE s.event_id = $1
+		GROUP BY s.id, s.name, s.sort_order, s.starts_at
+		ORDER BY s.sort_order ASC, s.starts_at ASC
+	`
+	if err := r.db.SelectContext(ctx, &stats, query, eventID); err != nil {
+		return nil, fmt.Errorf("eventRepository.GetSessionStats: %w", err)
+	}
+	return stats, nil
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -17,18 +20,57 @@ func NewGuestRepository(db *sqlx.DB) domain.GuestRepository {
 	return &guestRepository{db: db}
 }
 
+const insertGuestQuery = `
+	INSERT INTO guests (id, event_id, name, phone, message, rsvp_status, guest_code, guest_group, party_size, attendees, answers, session_ids, message_status, message_pinned, created_at)
+	VALUES (:id, :event_id, :name, :phone, :message, :rsvp_status, :guest_code, :guest_group, :party_size, :attendees, :answers, :session_ids, :message_status, :message_pinned, :created_at)
+`
+
 func (r *guestRepository) Create(ctx context.Context, guest *domain.Guest) error {
-	query := `
-		INSERT INTO guests (id, event_id, name, phone, message, rsvp_status, guest_code, created_at)
-		VALUES (:id, :event_id, :name, :phone, :message, :rsvp_status, :guest_code, :created_at)
-	`
-	_, err := r.db.NamedExecContext(ctx, query, guest)
+	_, err := r.db.NamedExecContext(ctx, insertGuestQuery, guest)
 	if err != nil {
 		return fmt.Errorf("guestRepository.Create: %w", err)
 	}
 	return nil
 }
 
+// CreateBatch inserts all guests in a single transaction.
+func (r *guestRepository) CreateBatch(ctx context.Context, guests []domain.Guest) error {
+	if len(guests) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("guestRepository.CreateBatch: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Keep each statement well under the postgres bind parameter limit
+	const chunkSize = 1000
+	for start := 0; start < len(guests); start += chunkSize {
+		end := start + chunkSize
+		if end > len(guests) {
+			end = len(guests)
+		}
+		if _, err := tx.NamedExecContext(ctx, insertGuestQuery, guests[start:end]); err != nil {
+			return fmt.Errorf("guestRepository.CreateBatch: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("guestRepository.CreateBatch: %w", err)
+	}
+	return nil
+}
+
+func (r *guestRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Guest, error) {
+	var guest domain.Guest
+	query := `SELECT * FROM guests WHERE id = $1`
+	if err := r.db.GetContext(ctx, &guest, query, id); err != nil {
+		return nil, fmt.Errorf("guestRepository.FindByID: %w", err)
+	}
+	return &guest, nil
+}
+
 func (r *guestRepository) FindByEventID(ctx context.Context, eventID uuid.UUID) ([]domain.Guest, error) {
 	var guests []domain.Guest
 	query := `SELECT * FROM guests WHERE event_id = $1 ORDER BY created_at DESC`
@@ -42,18 +84,213 @@ func (r *guestRepository) FindByGuestCode(ctx context.Context, code string) (*do
 	var guest domain.Guest
 	query := `SELECT * FROM guests WHERE guest_code = $1`
 	if err := r.db.GetContext(ctx, &guest, query, code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
 		return nil, fmt.Errorf("guestRepository.FindByGuestCode: %w", err)
 	}
 	return &guest, nil
 }
 
-func (r *guestRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.RSVPStatus) error {
+func (r *guestRepository) GuestCodeExists(ctx context.Context, code string) (bool, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(1) FROM guests WHERE guest_code = $1`, code)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *guestRepository) Update(ctx context.Context, guest *domain.Guest) error {
+	query := `
+		UPDATE guests SET
+			name = :name,
+			phone = :phone,
+			guest_group = :guest_group,
+			party_size = :party_size
+		WHERE id = :id AND event_id = :event_id
+	`
+	_, err := r.db.NamedExecContext(ctx, query, guest)
+	if err != nil {
+		return fmt.Errorf("guestRepository.Update: %w", err)
+	}
+	return nil
+}
+
+func (r *guestRepository) SaveRSVP(ctx context.Context, w *domain.RSVPWrite) error {
+	guest := w.Guest
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("guestRepository.SaveRSVP: %w", err)
+	}
+	defer tx.Rollback()
+
+	if w.Decide != nil {
+		// Capacity checks of one event run one at a time
+		var locked uuid.UUID
+		if err := tx.GetContext(ctx, &locked, `SELECT id FROM events WHERE id = $1 FOR UPDATE`, guest.EventID); err != nil {
+			return fmt.Errorf("guestRepository.SaveRSVP: %w", err)
+		}
+		var headcount int
+		query := `
+			SELECT COALESCE(SUM(attendees), 0) FROM guests
+			WHERE event_id = $1 AND rsvp_status = 'yes' AND id != $2
+		`
+		if err := tx.GetContext(ctx, &headcount, query, guest.EventID, guest.ID); err != nil {
+			return fmt.Errorf("guestRepository.SaveRSVP: %w", err)
+		}
+		status, err := w.Decide(headcount)
+		if err != nil {
+			return err
+		}
+		guest.RSVPStatus = status
+	}
+
+	if w.Create {
+		_, err = tx.NamedExecContext(ctx, insertGuestQuery, guest)
+	} else {
+		query := `
+			UPDATE guests SET
+				rsvp_status = :rsvp_status,
+				attendees = :attendees,
+				phone = :phone,
+				message = :message,
+				answers = :answers,
+				session_ids = :session_ids,
+				message_status = :message_status
+			WHERE id = :id
+		`
+		_, err = tx.NamedExecContext(ctx, query, guest)
+	}
+	if err != nil {
+		return fmt.Errorf("guestRepository.SaveRSVP: %w", err)
+	}
+
+	if change := w.Change; change != nil {
+		change.ToStatus, change.ToAttendees = guest.RSVPStatus, guest.Attendees
+		if change.FromStatus != change.ToStatus || change.FromAttendees != change.ToAttendees {
+			if _, err := tx.NamedExecContext(ctx, insertChangeQuery, change); err != nil {
+				return fmt.Errorf("guestRepository.SaveRSVP: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("guestRepository.SaveRSVP: %w", err)
+	}
+	return nil
+}
+
+func (r *guestRepository) MarkCheckedIn(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE guests SET checked_in_at = $1 WHERE id = $2 AND checked_in_at IS NULL`,
+		at, id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("guestRepository.MarkCheckedIn: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("guestRepository.MarkCheckedIn: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *guestRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM guests WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("guestRepository.Delete: %w", err)
+	}
+	return nil
+}
+
+// Wishes
+
+func (r *guestRepository) FindApprovedWishes(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]domain.Wish, error) {
+	var wishes []domain.Wish
+	query := `
+		SELECT id, name, message, message_pinned, created_at FROM guests
+		WHERE event_id = $1 AND message_status = 'approved' AND message IS NOT NULL AND message != ''
+		ORDER BY message_pinned DESC, created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	if err := r.db.SelectContext(ctx, &wishes, query, eventID, limit, offset); err != nil {
+		return nil, fmt.Errorf("guestRepository.FindApprovedWishes: %w", err)
+	}
+	return wishes, nil
+}
+
+func (r *guestRepository) CountApprovedWishes(ctx context.Context, eventID uuid.UUID) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(1) FROM guests
+		WHERE event_id = $1 AND message_status = 'approved' AND message IS NOT NULL AND message != ''
+	`
+	if err := r.db.GetContext(ctx, &count, query, eventID); err != nil {
+		return 0, fmt.Errorf("guestRepository.CountApprovedWishes: %w", err)
+	}
+	return count, nil
+}
+
+// FindMessagesByEventID returns guests with a message, optionally filtered
+// by moderation status (empty status returns all).
+func (r *guestRepository) FindMessagesByEventID(ctx context.Context, eventID uuid.UUID, status domain.MessageStatus) ([]domain.Guest, error) {
+	var guests []domain.Guest
+	query := `SELECT * FROM guests WHERE event_id = $1 AND message IS NOT NULL AND message != ''`
+	args := []interface{}{eventID}
+
+	if status != "" {
+		query += ` AND message_status = $2`
+		args = append(args, status)
+	}
+	query += ` ORDER BY message_pinned DESC, created_at DESC`
+
+	if err := r.db.SelectContext(ctx, &guests, query, args...); err != nil {
+		return nil, fmt.Errorf("guestRepository.FindMessagesByEventID: %w", err)
+	}
+	return guests, nil
+}
+
+func (r *guestRepository) UpdateMessageStatus(ctx context.Context, id uuid.UUID, status domain.MessageStatus) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE guests SET message_status = $1 WHERE id = $2`, status, id)
+	if err != nil {
+		return fmt.Errorf("guestRepository.UpdateMessageStatus: %w", err)
+	}
+	return nil
+}
+
+func (r *guestRepository) UpdateMessagePinned(ctx context.Context, id uuid.UUID, pinned bool) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE guests SET message_pinned = $1 WHERE id = $2`, pinned, id)
+	if err != nil {
+		return fmt.Errorf("guestRepository.UpdateMessagePinned: %w", err)
+	}
+	return nil
+}
+
+// ClearMessage deletes a guest's wish while keeping their RSVP.
+func (r *guestRepository) ClearMessage(ctx context.Context, id uuid.UUID) error {
 	_, err := r.db.ExecContext(ctx,
-		`UPDATE guests SET rsvp_status = $1 WHERE id = $2`,
-		status, id,
+		`UPDATE guests SET message = NULL, message_status = 'pending', message_pinned = FALSE WHERE id = $1`,
+		id,
 	)
 	if err != nil {
-		return fmt.Errorf("guestRepository.UpdateStatus: %w", err)
+		return fmt.Errorf("guestRepository.ClearMessage: %w", err)
 	}
 	return nil
 }
+
+// RSVP history
+
+const insertChangeQuery = `
+	INSERT INTO guest_rsvp_changes (id, guest_id, from_status, to_status, from_attendees, to_attendees, source, created_at)
+	VALUES (:id, :guest_id, :from_status, :to_status, :from_attendees, :to_attendees, :source, :created_at)
+`
+
+func (r *guestRepository) FindChangesByGuestID(ctx context.Context, guestID uuid.UUID) ([]domain.GuestRSVPChange, error) {
+	var changes []domain.GuestRSVPChange
+	query := `SELECT * FROM guest_rsvp_changes WHERE guest_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &changes, query, guestID); err != nil {
+		return nil, fmt.Errorf("guestRepository.FindChangesByGuestID: %w", err)
+	}
+	return changes, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/galihaleanda/event-invitation/internal/domain"
 )
 
@@ -17,21 +18,39 @@ func NewMediaRepository(db *sqlx.DB) domain.MediaRepository {
 	return &mediaRepository{db: db}
 }
 
+// Create appends the media to the end of the event's gallery.
 func (r *mediaRepository) Create(ctx context.Context, media *domain.Media) error {
 	query := `
-		INSERT INTO media (id, event_id, file_url, media_type, created_at)
-		VALUES (:id, :event_id, :file_url, :media_type, :created_at)
+		INSERT INTO media (id, event_id, file_url, media_type, storage_key, size_bytes, processing_status, caption,
+			approval_status, guest_id, uploader_name, sort_order, created_at)
+		VALUES (:id, :event_id, :file_url, :media_type, :storage_key, :size_bytes, :processing_status, :caption,
+			:approval_status, :guest_id, :uploader_name,
+			(SELECT COALESCE(MAX(sort_order) + 1, 0) FROM media WHERE event_id = :event_id), :created_at)
+		RETURNING sort_order
 	`
-	_, err := r.db.NamedExecContext(ctx, query, media)
+	stmt, err := r.db.PrepareNamedContext(ctx, query)
 	if err != nil {
 		return fmt.Errorf("mediaRepository.Create: %w", err)
 	}
+	defer stmt.Close()
+	if err := stmt.GetContext(ctx, &media.SortOrder, media); err != nil {
+		return fmt.Errorf("mediaRepository.Create: %w", err)
+	}
 	return nil
 }
 
+func (r *mediaRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Media, error) {
+	var media domain.Media
+	query := `SELECT * FROM media WHERE id = $1`
+	if err := r.db.GetContext(ctx, &media, query, id); err != nil {
+		return nil, fmt.Errorf("mediaRepository.FindByID: %w", err)
+	}
+	return &media, nil
+}
+
 func (r *mediaRepository) FindByEventID(ctx context.Context, eventID uuid.UUID) ([]domain.Media, error) {
 	var media []domain.Media
-	query := `SELECT * FROM media WHERE event_id = $1 ORDER BY created_at DESC`
+	query := `SELECT * FROM media WHERE event_id = $1 ORDER BY sort_order ASC, created_at ASC`
 	if err := r.db.SelectContext(ctx, &media, query, eventID); err != nil {
 		return nil, fmt.Errorf("mediaRepository.FindByEventID: %w", err)
 	}
@@ -45,3 +64,204 @@ func (r *mediaRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 	return nil
 }
+
+func (r *mediaRepository) Update(ctx context.Context, media *domain.Media) error {
+	query := `
+		UPDATE media SET
+			caption = :caption,
+			alt_text = :alt_text,
+			album_id = :album_id
+		WHERE id = :id AND event_id = :event_id
+	`
+	_, err := r.db.NamedExecContext(ctx, query, media)
+	if err != nil {
+		return fmt.Errorf("mediaRepository.Update: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) UpdateApprovalStatus(ctx context.Context, id uuid.UUID, status string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE media SET approval_status = $1 WHERE id = $2`, status, id)
+	if err != nil {
+		return fmt.Errorf("mediaRepository.UpdateApprovalStatus: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) SetCover(ctx context.Context, eventID uuid.UUID, mediaID *uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("mediaRepository.SetCover: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE media SET is_cover = FALSE WHERE event_id = $1 AND is_cover`, eventID); err != nil {
+		return fmt.Errorf("mediaRepository.SetCover: %w", err)
+	}
+	if mediaID != nil {
+		_, err := tx.ExecContext(ctx, `UPDATE media SET is_cover = TRUE WHERE id = $1 AND event_id = $2`, *mediaID, eventID)
+		if err != nil {
+			return fmt.Errorf("mediaRepository.SetCover: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("mediaRepository.SetCover: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) Reorder(ctx context.Context, eventID uuid.UUID, mediaIDs []uuid.UUID) error {
+	ids := make(pq.StringArray, len(mediaIDs))
+	for i, id := range mediaIDs {
+		ids[i] = id.String()
+	}
+
+	// Listed media take their position in the array, the rest follow in
+	// their current order
+	query := `
+		UPDATE media m SET sort_order = o.pos
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (
+				ORDER BY array_position($2::text[], id::text) NULLS LAST, sort_order, created_at
+			) - 1 AS pos
+			FROM media WHERE event_id = $1
+		) o
+		WHERE m.id = o.id
+	`
+	if _, err := r.db.ExecContext(ctx, query, eventID, ids); err != nil {
+		return fmt.Errorf("mediaRepository.Reorder: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) StorageUsedByEvent(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	var used int64
+	query := `
+		SELECT
+			COALESCE((SELECT SUM(size_bytes) FROM media WHERE event_id = $1 AND approval_status != 'pending'), 0) +
+			COALESCE((SELECT SUM(size_bytes) FROM media_uploads WHERE event_id = $1 AND expires_at > NOW()), 0)
+	`
+	if err := r.db.GetContext(ctx, &used, query, eventID); err != nil {
+		return 0, fmt.Errorf("mediaRepository.StorageUsedByEvent: %w", err)
+	}
+	return used, nil
+}
+
+func (r *mediaRepository) PendingStorageByEvent(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	var used int64
+	query := `SELECT COALESCE(SUM(size_bytes), 0) FROM media WHERE event_id = $1 AND approval_status = 'pending'`
+	if err := r.db.GetContext(ctx, &used, query, eventID); err != nil {
+		return 0, fmt.Errorf("mediaRepository.PendingStorageByEvent: %w", err)
+	}
+	return used, nil
+}
+
+func (r *mediaRepository) FindPendingIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := `SELECT id FROM media WHERE processing_status = 'pending' ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &ids, query); err != nil {
+		return nil, fmt.Errorf("mediaRepository.FindPendingIDs: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *mediaRepository) UpdateProcessing(ctx context.Context, media *domain.Media) (bool, error) {
+	query := `
+		UPDATE media
+		SET width = :width, height = :height, variants = :variants, size_bytes = :size_bytes,
+		    processing_status = :processing_status
+		WHERE id = :id
+	`
+	res, err := r.db.NamedExecContext(ctx, query, media)
+	if err != nil {
+		return false, fmt.Errorf("mediaRepository.UpdateProcessing: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *mediaRepository) CreateUpload(ctx context.Context, upload *domain.MediaUpload) error {
+	query := `
+		INSERT INTO media_uploads (id, event_id, storage_key, media_type, content_type, size_bytes, created_by, expires_at, created_at)
+		VALUES (:id, :event_id, :storage_key, :media_type, :content_type, :size_bytes, :created_by, :expires_at, :created_at)
+	`
+	_, err := r.db.NamedExecContext(ctx, query, upload)
+	if err != nil {
+		return fmt.Errorf("mediaRepository.CreateUpload: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) FindUploadByID(ctx context.Context, id uuid.UUID) (*domain.MediaUpload, error) {
+	var upload domain.MediaUpload
+	query := `SELECT * FROM media_uploads WHERE id = $1`
+	if err := r.db.GetContext(ctx, &upload, query, id); err != nil {
+		return nil, fmt.Errorf("mediaRepository.FindUploadByID: %w", err)
+	}
+	return &upload, nil
+}
+
+func (r *mediaRepository) FindUploadByKey(ctx context.Context, key string) (*domain.MediaUpload, error) {
+	var upload domain.MediaUpload
+	query := `SELECT * FROM media_uploads WHERE storage_key = $1`
+	if err := r.db.GetContext(ctx, &upload, query, key); err != nil {
+		return nil, fmt.Errorf("mediaRepository.FindUploadByKey: %w", err)
+	}
+	return &upload, nil
+}
+
+func (r *mediaRepository) DeleteUpload(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM media_uploads WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("mediaRepository.DeleteUpload: %w", err)
+	}
+	return nil
+}
+
+// Albums
+
+func (r *mediaRepository) CreateAlbum(ctx context.Context, album *domain.MediaAlbum) error {
+	query := `
+		INSERT INTO media_albums (id, event_id, title, sort_order, created_at)
+		VALUES (:id, :event_id, :title, :sort_order, :created_at)
+	`
+	_, err := r.db.NamedExecContext(ctx, query, album)
+	if err != nil {
+		return fmt.Errorf("mediaRepository.CreateAlbum: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) FindAlbumsByEventID(ctx context.Context, eventID uuid.UUID) ([]domain.MediaAlbum, error) {
+	var albums []domain.MediaAlbum
+	query := `SELECT * FROM media_albums WHERE event_id = $1 ORDER BY sort_order ASC, created_at ASC`
+	if err := r.db.SelectContext(ctx, &albums, query, eventID); err != nil {
+		return nil, fmt.Errorf("mediaRepository.FindAlbumsByEventID: %w", err)
+	}
+	return albums, nil
+}
+
+func (r *mediaRepository) UpdateAlbum(ctx context.Context, album *domain.MediaAlbum) error {
+	query := `
+		UPDATE media_albums SET
+			title = :title,
+			sort_order = :sort_order
+		WHERE id = :id AND event_id = :event_id
+	`
+	_, err := r.db.NamedExecContext(ctx, query, album)
+	if err != nil {
+		return fmt.Errorf("mediaRepository.UpdateAlbum: %w", err)
+	}
+	return nil
+}
+
+// DeleteAlbum removes the album; its media stay in the gallery without an
+// album.
+func (r *mediaRepository) DeleteAlbum(ctx context.Context, eventID, albumID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM media_albums WHERE id = $1 AND event_id = $2`, albumID, eventID)
+	if err != nil {
+		return fmt.Errorf("mediaRepository.DeleteAlbum: %w", err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/galihaleanda/event-invitation/internal/domain"
 )
 
@@ -17,16 +18,16 @@ func NewTemplateRepository(db *sqlx.DB) domain.TemplateRepository {
 	return &templateRepository{db: db}
 }
 
-func (r *templateRepository) FindAll(ctx context.Context, category string) ([]domain.Template, error) {
+func (r *templateRepository) FindAll(ctx context.Context, category string, includeInactive bool) ([]domain.Template, error) {
 	var templates []domain.Template
-	query := `SELECT * FROM templates WHERE is_active = true`
-	args := []interface{}{}
+	query := `SELECT * FROM templates WHERE (is_active = true OR $1)`
+	args := []interface{}{includeInactive}
 
 	if category != "" {
-		query += ` AND category = $1`
+		query += ` AND category = $2`
 		args = append(args, category)
 	}
-	query += ` ORDER BY created_at DESC`
+	query += ` ORDER BY sort_order ASC, created_at DESC`
 
 	if err := r.db.SelectContext(ctx, &templates, query, args...); err != nil {
 		return nil, fmt.Errorf("templateRepository.FindAll: %w", err)
@@ -43,6 +44,98 @@ func (r *templateRepository) FindByID(ctx context.Context, id uuid.UUID) (*domai
 	return &tmpl, nil
 }
 
+// snapshotSectionsQuery stores the current sections of template $1 as
+// version $2.
+const snapshotSectionsQuery = `
+	INSERT INTO template_versions (template_id, version, sections, created_at)
+	SELECT $1, $2, COALESCE((
+		SELECT jsonb_agg(jsonb_build_object(
+			'id', s.id,
+			'template_id', s.template_id,
+			'name', s.name,
+			'type', s.type,
+			'default_content', s.default_content,
+			'sort_order', s.sort_order
+		) ORDER BY s.sort_order)
+		FROM template_sections s WHERE s.template_id = $1
+	), '[]'::jsonb), NOW()
+`
+
+// Create inserts the template at version 1, with an empty snapshot.
+func (r *templateRepository) Create(ctx context.Context, tmpl *domain.Template) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("templateRepository.Create: %w", err)
+	}
+	defer tx.Rollback()
+
+	tmpl.Version = 1
+	query := `
+		INSERT INTO templates (id, name, category, thumbnail_url, thumbnail_key, is_active, sort_order, version, created_at, updated_at)
+		VALUES (:id, :name, :category, :thumbnail_url, :thumbnail_key, :is_active,
+			(SELECT COALESCE(MAX(sort_order) + 1, 0) FROM templates), :version, :created_at, :updated_at)
+		RETURNING sort_order
+	`
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("templateRepository.Create: %w", err)
+	}
+	defer stmt.Close()
+	if err := stmt.GetContext(ctx, &tmpl.SortOrder, tmpl); err != nil {
+		return fmt.Errorf("templateRepository.Create: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, snapshotSectionsQuery, tmpl.ID, tmpl.Version); err != nil {
+		return fmt.Errorf("templateRepository.Create: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("templateRepository.Create: %w", err)
+	}
+	return nil
+}
+
+func (r *templateRepository) Update(ctx context.Context, tmpl *domain.Template) error {
+	query := `
+		UPDATE templates SET
+			name = :name,
+			category = :category,
+			thumbnail_url = :thumbnail_url,
+			thumbnail_key = :thumbnail_key,
+			is_active = :is_active,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+	_, err := r.db.NamedExecContext(ctx, query, tmpl)
+	if err != nil {
+		return fmt.Errorf("templateRepository.Update: %w", err)
+	}
+	return nil
+}
+
+func (r *templateRepository) Reorder(ctx context.Context, templateIDs []uuid.UUID) error {
+	ids := make(pq.StringArray, len(templateIDs))
+	for i, id := range templateIDs {
+		ids[i] = id.String()
+	}
+
+	// Listed templates take their position in the array, the rest follow
+	// in their current order
+	query := `
+		UPDATE templates t SET sort_order = o.pos
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (
+				ORDER BY array_position($1::text[], id::text) NULLS LAST, sort_order, created_at DESC
+			) - 1 AS pos
+			FROM templates
+		) o
+		WHERE t.id = o.id
+	`
+	if _, err := r.db.ExecContext(ctx, query, ids); err != nil {
+		return fmt.Errorf("templateRepository.Reorder: %w", err)
+	}
+	return nil
+}
+
 func (r *templateRepository) FindSectionsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]domain.TemplateSection, error) {
 	var sections []domain.TemplateSection
 	query := `SELECT * FROM template_sections WHERE template_id = $1 ORDER BY sort_order ASC`
@@ -51,3 +144,152 @@ func (r *templateRepository) FindSectionsByTemplateID(ctx context.Context, templ
 	}
 	return sections, nil
 }
+
+// Section changes publish a new template version in the same transaction,
+// so a version snapshot always matches the sections it was taken from.
+
+func (r *templateRepository) CreateSection(ctx context.Context, section *domain.TemplateSection) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("templateRepository.CreateSection: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO template_sections (id, template_id, name, type, default_content, sort_order)
+		VALUES (:id, :template_id, :name, :type, :default_content,
+			(SELECT COALESCE(MAX(sort_order) + 1, 0) FROM template_sections WHERE template_id = :template_id))
+		RETURNING sort_order
+	`
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("templateRepository.CreateSection: %w", err)
+	}
+	defer stmt.Close()
+	if err := stmt.GetContext(ctx, &section.SortOrder, section); err != nil {
+		return fmt.Errorf("templateRepository.CreateSection: %w", err)
+	}
+	if err := publishVersion(ctx, tx, section.TemplateID); err != nil {
+		return fmt.Errorf("templateRepository.CreateSection: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("templateRepository.CreateSection: %w", err)
+	}
+	return nil
+}
+
+func (r *templateRepository) UpdateSection(ctx context.Context, section *domain.TemplateSection) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("templateRepository.UpdateSection: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE template_sections SET
+			name = :name,
+			type = :type,
+			default_content = :default_content
+		WHERE id = :id AND template_id = :template_id
+	`
+	if _, err := tx.NamedExecContext(ctx, query, section); err != nil {
+		return fmt.Errorf("templateRepository.UpdateSection: %w", err)
+	}
+	if err := publishVersion(ctx, tx, section.TemplateID); err != nil {
+		return fmt.Errorf("templateRepository.UpdateSection: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("templateRepository.UpdateSection: %w", err)
+	}
+	return nil
+}
+
+func (r *templateRepository) DeleteSection(ctx context.Context, templateID, id uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("templateRepository.DeleteSection: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM template_sections WHERE id = $1 AND template_id = $2`
+	if _, err := tx.ExecContext(ctx, query, id, templateID); err != nil {
+		return fmt.Errorf("templateRepository.DeleteSection: %w", err)
+	}
+	if err := publishVersion(ctx, tx, templateID); err != nil {
+		return fmt.Errorf("templateRepository.DeleteSection: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("templateRepository.DeleteSection: %w", err)
+	}
+	return nil
+}
+
+func (r *templateRepository) SectionInUse(ctx context.Context, id uuid.UUID) (bool, error) {
+	var inUse bool
+	query := `SELECT EXISTS (SELECT 1 FROM event_sections WHERE template_section_id = $1)`
+	if err := r.db.GetContext(ctx, &inUse, query, id); err != nil {
+		return false, fmt.Errorf("templateRepository.SectionInUse: %w", err)
+	}
+	return inUse, nil
+}
+
+func (r *templateRepository) ReorderSections(ctx context.Context, templateID uuid.UUID, sectionIDs []uuid.UUID) error {
+	ids := make(pq.StringArray, len(sectionIDs))
+	for i, id := range sectionIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		UPDATE template_sections s SET sort_order = o.pos
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (
+				ORDER BY array_position($2::text[], id::text) NULLS LAST, sort_order
+			) - 1 AS pos
+			FROM template_sections WHERE template_id = $1
+		) o
+		WHERE s.id = o.id
+	`
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("templateRepository.ReorderSections: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query, templateID, ids); err != nil {
+		return fmt.Errorf("templateRepository.ReorderSections: %w", err)
+	}
+	if err := publishVersion(ctx, tx, templateID); err != nil {
+		return fmt.Errorf("templateRepository.ReorderSections: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("templateRepository.ReorderSections: %w", err)
+	}
+	return nil
+}
+
+// Versions
+
+// publishVersion bumps the template version and snapshots its current
+// sections as that version, which existing events can then upgrade to.
+func publishVersion(ctx context.Context, tx *sqlx.Tx, templateID uuid.UUID) error {
+	var version int
+	query := `UPDATE templates SET version = version + 1, updated_at = NOW() WHERE id = $1 RETURNING version`
+	if err := tx.GetContext(ctx, &version, query, templateID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, snapshotSectionsQuery, templateID, version)
+	return err
+}
+
+func (r *templateRepository) FindVersion(ctx context.Context, templateID uuid.UUID, version int) (*domain.TemplateVersion, error) {
+	var v domain.TemplateVersion
+	query := `SELECT * FROM template_versions WHERE template_id = $1 AND version = $2`
+	if err := r.db.GetContext(ctx, &v, query, templateID, version); err != nil {
+		return nil, fmt.Errorf("templateRepository.FindVersion: %w", err)
+	}
+	return &v, nil
+}
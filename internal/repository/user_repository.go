@@ -19,8 +19,8 @@ func NewUserRepository(db *sqlx.DB) domain.UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, name, email, password_hash, created_at, updated_at)
-		VALUES (:id, :name, :email, :password_hash, :created_at, :updated_at)
+		INSERT INTO users (id, name, email, password_hash, role, created_at, updated_at)
+		VALUES (:id, :name, :email, :password_hash, :role, :created_at, :updated_at)
 	`
 	_, err := r.db.NamedExecContext(ctx, query, user)
 	if err != nil {
@@ -16,6 +16,8 @@ import (
 type AppError struct {
 	Code    int
 	Message string
+	// Data describes the failure in detail, e.g. field validation errors
+	Data interface{}
 }
 
 func (e *AppError) Error() string {
@@ -26,6 +28,15 @@ func NewAppError(code int, message string) *AppError {
 	return &AppError{Code: code, Message: message}
 }
 
+// NewValidationError is a 422 listing the invalid fields.
+func NewValidationError(message string, errs []domain.FieldError) *AppError {
+	return &AppError{
+		Code:    http.StatusUnprocessableEntity,
+		Message: message,
+		Data:    map[string]interface{}{"errors": errs},
+	}
+}
+
 type AuthService interface {
 	Register(ctx context.Context, req *domain.RegisterRequest) (*domain.AuthResponse, error)
 	Login(ctx context.Context, req *domain.LoginRequest) (*domain.AuthResponse, error)
@@ -59,6 +70,7 @@ func (s *authService) Register(ctx context.Context, req *domain.RegisterRequest)
 		Name:         req.Name,
 		Email:        req.Email,
 		PasswordHash: string(hash),
+		Role:         domain.UserRoleUser,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+// Action is something a user does on an event. Every owner-side service
+// method authorizes exactly one action through the Authorizer.
+type Action string
+
+const (
+	// ActionView reads event settings, stats, questions, sessions and media.
+	ActionView Action = "view"
+	// ActionViewGuests reads the guest list, its history, exports and QR codes.
+	ActionViewGuests Action = "view_guests"
+	// ActionEdit changes event content, theme, sections, questions,
+	// sessions and media, and publishes the event.
+	ActionEdit Action = "edit"
+	// ActionManageGuests adds, edits, imports and removes guests and
+	// moderates wishes.
+	ActionManageGuests Action = "manage_guests"
+	ActionCheckIn      Action = "check_in"
+	// ActionManage deletes the event and manages its collaborators.
+	ActionManage Action = "manage"
+)
+
+var rolePermissions = map[domain.CollaboratorRole][]Action{
+	domain.CollaboratorRoleOwner: {
+		ActionView, ActionViewGuests, ActionEdit, ActionManageGuests, ActionCheckIn, ActionManage,
+	},
+	domain.CollaboratorRoleEditor: {
+		ActionView, ActionViewGuests, ActionEdit, ActionManageGuests, ActionCheckIn,
+	},
+	domain.CollaboratorRoleUsher: {
+		ActionViewGuests, ActionCheckIn,
+	},
+	domain.CollaboratorRoleViewer: {
+		ActionView, ActionViewGuests,
+	},
+}
+
+// Authorizer is the single place that decides who may do what on an event.
+type Authorizer interface {
+	// Authorize loads the event and checks that the user's role on it
+	// allows the action. It returns 404 for unknown events and 403 otherwise.
+	Authorize(ctx context.Context, userID, eventID uuid.UUID, action Action) (*domain.Event, error)
+	// Role returns the user's role on the event, or "" for non-members.
+	Role(ctx context.Context, userID uuid.UUID, event *domain.Event) (domain.CollaboratorRole, error)
+}
+
+type authorizer struct {
+	eventRepo        domain.EventRepository
+	collaboratorRepo domain.CollaboratorRepository
+}
+
+func NewAuthorizer(eventRepo domain.EventRepository, collaboratorRepo domain.CollaboratorRepository) Authorizer {
+	return &authorizer{eventRepo: eventRepo, collaboratorRepo: collaboratorRepo}
+}
+
+func (a *authorizer) Authorize(ctx context.Context, userID, eventID uuid.UUID, action Action) (*domain.Event, error) {
+	event, err := a.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, NewAppError(http.StatusNotFound, "event not found")
+	}
+
+	role, err := a.Role(ctx, userID, event)
+	if err != nil {
+		return nil, err
+	}
+	if !roleAllows(role, action) {
+		return nil, NewAppError(http.StatusForbidden, "forbidden")
+	}
+	return event, nil
+}
+
+func (a *authorizer) Role(ctx context.Context, userID uuid.UUID, event *domain.Event) (domain.CollaboratorRole, error) {
+	if event.UserID == userID {
+		return domain.CollaboratorRoleOwner, nil
+	}
+	member, err := a.collaboratorRepo.FindMember(ctx, event.ID, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if member == nil {
+		return "", nil
+	}
+	return member.Role, nil
+}
+
+func roleAllows(role domain.CollaboratorRole, action Action) bool {
+	for _, allowed := range rolePermissions[role] {
+		if allowed == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+func TestRoleAllows(t *testing.T) {
+	actions := []Action{ActionView, ActionViewGuests, ActionEdit, ActionManageGuests, ActionCheckIn, ActionManage}
+
+	tests := []struct {
+		role    domain.CollaboratorRole
+		allowed []Action
+	}{
+		{
+			role:    domain.CollaboratorRoleOwner,
+			allowed: []Action{ActionView, ActionViewGuests, ActionEdit, ActionManageGuests, ActionCheckIn, ActionManage},
+		},
+		{
+			role:    domain.CollaboratorRoleEditor,
+			allowed: []Action{ActionView, ActionViewGuests, ActionEdit, ActionManageGuests, ActionCheckIn},
+		},
+		{
+			role:    domain.CollaboratorRoleUsher,
+			allowed: []Action{ActionViewGuests, ActionCheckIn},
+		},
+		{
+			role:    domain.CollaboratorRoleViewer,
+			allowed: []Action{ActionView, ActionViewGuests},
+		},
+		{role: ""},
+		{role: "admin"},
+	}
+
+	for _, tt := range tests {
+		want := make(map[Action]bool, len(tt.allowed))
+		for _, a := range tt.allowed {
+			want[a] = true
+		}
+		for _, action := range actions {
+			name := string(tt.role) + "/" + string(action)
+			if tt.role == "" {
+				name = "non-member/" + string(action)
+			}
+			t.Run(name, func(t *testing.T) {
+				if got := roleAllows(tt.role, action); got != want[action] {
+					t.Errorf("roleAllows(%q, %q) = %v, want %v", tt.role, action, got, want[action])
+				}
+			})
+		}
+	}
+}
+
+type fakeAuthzEventRepo struct {
+	domain.EventRepository
+	event *domain.Event
+}
+
+func (r *fakeAuthzEventRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	if r.event == nil || r.event.ID != id {
+		return nil, errors.New("not found")
+	}
+	return r.event, nil
+}
+
+type fakeAuthzCollaboratorRepo struct {
+	domain.CollaboratorRepository
+	members map[uuid.UUID]domain.CollaboratorRole
+}
+
+func (r *fakeAuthzCollaboratorRepo) FindMember(ctx context.Context, eventID, userID uuid.UUID) (*domain.EventCollaborator, error) {
+	role, ok := r.members[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &domain.EventCollaborator{EventID: eventID, UserID: &userID, Role: role}, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	owner, editor, usher, stranger := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	event := &domain.Event{ID: uuid.New(), UserID: owner}
+	authz := NewAuthorizer(
+		&fakeAuthzEventRepo{event: event},
+		&fakeAuthzCollaboratorRepo{members: map[uuid.UUID]domain.CollaboratorRole{
+			editor: domain.CollaboratorRoleEditor,
+			usher:  domain.CollaboratorRoleUsher,
+		}},
+	)
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		eventID  uuid.UUID
+		action   Action
+		wantCode int
+	}{
+		{name: "creator is owner", userID: owner, eventID: event.ID, action: ActionManage},
+		{name: "editor edits", userID: editor, eventID: event.ID, action: ActionEdit},
+		{name: "editor cannot manage", userID: editor, eventID: event.ID, action: ActionManage, wantCode: http.StatusForbidden},
+		{name: "usher checks in", userID: usher, eventID: event.ID, action: ActionCheckIn},
+		{name: "usher cannot view settings", userID: usher, eventID: event.ID, action: ActionView, wantCode: http.StatusForbidden},
+		{name: "stranger", userID: stranger, eventID: event.ID, action: ActionView, wantCode: http.StatusForbidden},
+		{name: "unknown event", userID: owner, eventID: uuid.New(), action: ActionView, wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := authz.Authorize(context.Background(), tt.userID, tt.eventID, tt.action)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("Authorize() error = %v", err)
+				}
+				if got != event {
+					t.Errorf("Authorize() = %v, want the event", got)
+				}
+				return
+			}
+			var appErr *AppError
+			if !errors.As(err, &appErr) || appErr.Code != tt.wantCode {
+				t.Errorf("Authorize() error = %v, want %d", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+// Collaborators can never be made owners; an event has one, its creator.
+func TestCollaboratorRoleValidation(t *testing.T) {
+	validate := validator.New()
+	validate.SetTagName("binding")
+
+	tests := []struct {
+		role    domain.CollaboratorRole
+		wantErr bool
+	}{
+		{role: domain.CollaboratorRoleOwner, wantErr: true},
+		{role: domain.CollaboratorRoleEditor},
+		{role: domain.CollaboratorRoleUsher},
+		{role: domain.CollaboratorRoleViewer},
+		{role: "admin", wantErr: true},
+		{role: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			invite := domain.InviteCollaboratorRequest{Email: "rina@example.com", Role: tt.role}
+			if err := validate.Struct(invite); (err != nil) != tt.wantErr {
+				t.Errorf("invite as %q: error = %v, wantErr %v", tt.role, err, tt.wantErr)
+			}
+			update := domain.UpdateCollaboratorRequest{Role: tt.role}
+			if err := validate.Struct(update); (err != nil) != tt.wantErr {
+				t.Errorf("update to %q: error = %v, wantErr %v", tt.role, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/config"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/utils"
+)
+
+type CheckInService interface {
+	// TokenForGuest returns the QR token of a guest for the event owner.
+	TokenForGuest(ctx context.Context, userID, eventID, guestID uuid.UUID) (string, error)
+	// TokenForCode returns the QR token of the guest owning the guest code.
+	TokenForCode(ctx context.Context, code string) (string, error)
+	CheckIn(ctx context.Context, userID, eventID uuid.UUID, req *domain.CheckInRequest) (*domain.CheckInResult, error)
+}
+
+type checkInService struct {
+	guestRepo domain.GuestRepository
+	authz     Authorizer
+	secret    string
+}
+
+func NewCheckInService(guestRepo domain.GuestRepository, authz Authorizer, cfg *config.Config) CheckInService {
+	return &checkInService{guestRepo: guestRepo, authz: authz, secret: cfg.CheckIn.Secret}
+}
+
+func (s *checkInService) TokenForGuest(ctx context.Context, userID, eventID, guestID uuid.UUID) (string, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionViewGuests); err != nil {
+		return "", err
+	}
+
+	guest, err := s.guestRepo.FindByID(ctx, guestID)
+	if err != nil || guest.EventID != eventID {
+		return "", NewAppError(http.StatusNotFound, "guest not found")
+	}
+	return utils.SignCheckInToken(guest.ID, guest.EventID, s.secret), nil
+}
+
+func (s *checkInService) TokenForCode(ctx context.Context, code string) (string, error) {
+	guest, err := s.guestRepo.FindByGuestCode(ctx, code)
+	if err != nil {
+		return "", fmt.Errorf("failed to find guest: %w", err)
+	}
+	if guest == nil {
+		return "", NewAppError(http.StatusNotFound, "guest not found")
+	}
+	return utils.SignCheckInToken(guest.ID, guest.EventID, s.secret), nil
+}
+
+// CheckIn verifies a scanned token and marks the guest as arrived. The
+// update is conditional, so concurrent scans at two doors still count the
+// guest once. Guests who declined or are waitlisted are refused unless the
+// request overrides it.
+func (s *checkInService) CheckIn(ctx context.Context, userID, eventID uuid.UUID, req *domain.CheckInRequest) (*domain.CheckInResult, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionCheckIn); err != nil {
+		return nil, err
+	}
+
+	token := req.Token
+	guestID, err := utils.CheckInTokenGuestID(token)
+	if err != nil {
+		return nil, NewAppError(http.StatusBadRequest, "invalid check-in code")
+	}
+	guest, err := s.guestRepo.FindByID(ctx, guestID)
+	if err != nil || !utils.VerifyCheckInToken(token, guest.ID, guest.EventID, s.secret) {
+		return nil, NewAppError(http.StatusBadRequest, "invalid check-in code")
+	}
+	if guest.EventID != eventID {
+		return nil, NewAppError(http.StatusUnprocessableEntity, "guest is invited to another event")
+	}
+	if !req.Override && guest.CheckedInAt == nil &&
+		(guest.RSVPStatus == domain.RSVPStatusNo || guest.RSVPStatus == domain.RSVPStatusWaitlist) {
+		return nil, &AppError{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("guest RSVP is %q, send override to check them in anyway", guest.RSVPStatus),
+			Data:    &domain.CheckInResult{Guest: guest, RSVPStatus: guest.RSVPStatus},
+		}
+	}
+
+	now := time.Now()
+	marked, err := s.guestRepo.MarkCheckedIn(ctx, guest.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check in guest: %w", err)
+	}
+	if !marked {
+		// Re-scan: report the first check-in instead of counting again
+		current, err := s.guestRepo.FindByID(ctx, guest.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find guest: %w", err)
+		}
+		result := &domain.CheckInResult{Guest: current, RSVPStatus: current.RSVPStatus, AlreadyCheckedIn: true}
+		if current.CheckedInAt != nil {
+			result.CheckedInAt = *current.CheckedInAt
+		}
+		return result, nil
+	}
+
+	guest.CheckedInAt = &now
+	return &domain.CheckInResult{Guest: guest, RSVPStatus: guest.RSVPStatus, CheckedInAt: now}, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+type CollaboratorService interface {
+	List(ctx context.Context, userID, eventID uuid.UUID) ([]domain.EventCollaborator, error)
+	Invite(ctx context.Context, userID, eventID uuid.UUID, req *domain.InviteCollaboratorRequest) (*domain.EventCollaborator, error)
+	UpdateRole(ctx context.Context, userID, eventID, collaboratorID uuid.UUID, req *domain.UpdateCollaboratorRequest) (*domain.EventCollaborator, error)
+	// Remove revokes a collaborator; collaborators may also remove
+	// themselves to leave an event.
+	Remove(ctx context.Context, userID, eventID, collaboratorID uuid.UUID) error
+
+	// Invitations addressed to the signed-in user's email
+	MyInvitations(ctx context.Context, userID uuid.UUID) ([]domain.EventCollaborator, error)
+	Accept(ctx context.Context, userID, invitationID uuid.UUID) (*domain.EventCollaborator, error)
+	Decline(ctx context.Context, userID, invitationID uuid.UUID) error
+}
+
+type collaboratorService struct {
+	collaboratorRepo domain.CollaboratorRepository
+	userRepo         domain.UserRepository
+	authz            Authorizer
+}
+
+func NewCollaboratorService(collaboratorRepo domain.CollaboratorRepository, userRepo domain.UserRepository, authz Authorizer) CollaboratorService {
+	return &collaboratorService{collaboratorRepo: collaboratorRepo, userRepo: userRepo, authz: authz}
+}
+
+func (s *collaboratorService) List(ctx context.Context, userID, eventID uuid.UUID) ([]domain.EventCollaborator, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionView); err != nil {
+		return nil, err
+	}
+
+	collaborators, err := s.collaboratorRepo.FindByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collaborators: %w", err)
+	}
+	return collaborators, nil
+}
+
+// Invite records a pending invitation for an email address. The invitee
+// sees it under /invitations once they sign in with that email.
+func (s *collaboratorService) Invite(ctx context.Context, userID, eventID uuid.UUID, req *domain.InviteCollaboratorRequest) (*domain.EventCollaborator, error) {
+	event, err := s.authz.Authorize(ctx, userID, eventID, ActionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	owner, err := s.userRepo.FindByID(ctx, event.UserID)
+	if err == nil && strings.EqualFold(owner.Email, email) {
+		return nil, NewAppError(http.StatusBadRequest, "the event owner cannot be invited")
+	}
+	exists, err := s.collaboratorRepo.EmailExists(ctx, eventID, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check collaborators: %w", err)
+	}
+	if exists {
+		return nil, NewAppError(http.StatusConflict, "this email is already invited")
+	}
+
+	collaborator := &domain.EventCollaborator{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		Email:     email,
+		Role:      req.Role,
+		InvitedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.collaboratorRepo.Create(ctx, collaborator); err != nil {
+		return nil, fmt.Errorf("failed to invite collaborator: %w", err)
+	}
+	return collaborator, nil
+}
+
+func (s *collaboratorService) UpdateRole(ctx context.Context, userID, eventID, collaboratorID uuid.UUID, req *domain.UpdateCollaboratorRequest) (*domain.EventCollaborator, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionManage); err != nil {
+		return nil, err
+	}
+
+	collaborator, err := s.collaboratorRepo.FindByID(ctx, collaboratorID)
+	if err != nil || collaborator.EventID != eventID {
+		return nil, NewAppError(http.StatusNotFound, "collaborator not found")
+	}
+
+	if err := s.collaboratorRepo.UpdateRole(ctx, collaborator.ID, req.Role); err != nil {
+		return nil, fmt.Errorf("failed to update collaborator: %w", err)
+	}
+	collaborator.Role = req.Role
+	return collaborator, nil
+}
+
+func (s *collaboratorService) Remove(ctx context.Context, userID, eventID, collaboratorID uuid.UUID) error {
+	collaborator, err := s.collaboratorRepo.FindByID(ctx, collaboratorID)
+	if err != nil || collaborator.EventID != eventID {
+		return NewAppError(http.StatusNotFound, "collaborator not found")
+	}
+
+	leaving := collaborator.UserID != nil && *collaborator.UserID == userID
+	if !leaving {
+		if _, err := s.authz.Authorize(ctx, userID, eventID, ActionManage); err != nil {
+			return err
+		}
+	}
+	return s.collaboratorRepo.Delete(ctx, collaborator.ID)
+}
+
+func (s *collaboratorService) MyInvitations(ctx context.Context, userID uuid.UUID) ([]domain.EventCollaborator, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, NewAppError(http.StatusNotFound, "user not found")
+	}
+
+	invitations, err := s.collaboratorRepo.FindPendingByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+func (s *collaboratorService) Accept(ctx context.Context, userID, invitationID uuid.UUID) (*domain.EventCollaborator, error) {
+	invitation, err := s.findMyInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.collaboratorRepo.Accept(ctx, invitation.ID, userID, now); err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	invitation.UserID = &userID
+	invitation.AcceptedAt = &now
+	return invitation, nil
+}
+
+func (s *collaboratorService) Decline(ctx context.Context, userID, invitationID uuid.UUID) error {
+	invitation, err := s.findMyInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+	return s.collaboratorRepo.Delete(ctx, invitation.ID)
+}
+
+// findMyInvitation returns a pending invitation addressed to the user's
+// email; other users' invitations are reported as not found.
+func (s *collaboratorService) findMyInvitation(ctx context.Context, userID, invitationID uuid.UUID) (*domain.EventCollaborator, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, NewAppError(http.StatusNotFound, "user not found")
+	}
+
+	invitation, err := s.collaboratorRepo.FindByID(ctx, invitationID)
+	if err != nil || !strings.EqualFold(invitation.Email, user.Email) {
+		return nil, NewAppError(http.StatusNotFound, "invitation not found")
+	}
+	if invitation.AcceptedAt != nil {
+		return nil, NewAppError(http.StatusConflict, "invitation already accepted")
+	}
+	return invitation, nil
+}
@@ -5,40 +5,71 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/galihaleanda/event-invitation/internal/domain"
 	"github.com/galihaleanda/event-invitation/internal/utils"
 )
 
 type EventService interface {
 	Create(ctx context.Context, userID uuid.UUID, req *domain.CreateEventRequest) (*domain.Event, error)
-	GetByID(ctx context.Context, id uuid.UUID) (*domain.Event, error)
-	GetBySlug(ctx context.Context, slug string) (*domain.PublicEventResponse, error)
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*domain.Event, error)
+	GetBySlug(ctx context.Context, slug, guestCode string) (*domain.PublicEventResponse, error)
 	GetMyEvents(ctx context.Context, userID uuid.UUID) ([]domain.Event, error)
 	Update(ctx context.Context, userID, eventID uuid.UUID, req *domain.UpdateEventRequest) (*domain.Event, error)
 	Delete(ctx context.Context, userID, eventID uuid.UUID) error
 	Publish(ctx context.Context, userID, eventID uuid.UUID, publish bool) error
 	UpdateTheme(ctx context.Context, userID, eventID uuid.UUID, req *domain.UpdateThemeRequest) (*domain.EventTheme, error)
+
+	// Background music
+	GetMusic(ctx context.Context, userID, eventID uuid.UUID) (*domain.EventMusic, error)
+	UpdateMusic(ctx context.Context, userID, eventID uuid.UUID, req *domain.UpdateMusicRequest) (*domain.EventMusic, error)
+	DeleteMusic(ctx context.Context, userID, eventID uuid.UUID) error
+
 	UpdateSection(ctx context.Context, userID, eventID, sectionID uuid.UUID, req *domain.UpdateSectionRequest) (*domain.EventSection, error)
+	GetStats(ctx context.Context, userID, eventID uuid.UUID) (*domain.EventStats, error)
+
+	// Template upgrade
+	GetTemplateUpgrade(ctx context.Context, userID, eventID uuid.UUID) (*domain.TemplateUpgrade, error)
+	UpgradeTemplate(ctx context.Context, userID, eventID uuid.UUID) (*domain.TemplateUpgrade, error)
+
+	// RSVP questions
+	GetQuestions(ctx context.Context, userID, eventID uuid.UUID) ([]domain.EventQuestion, error)
+	CreateQuestion(ctx context.Context, userID, eventID uuid.UUID, req *domain.CreateQuestionRequest) (*domain.EventQuestion, error)
+	UpdateQuestion(ctx context.Context, userID, eventID, questionID uuid.UUID, req *domain.UpdateQuestionRequest) (*domain.EventQuestion, error)
+	DeleteQuestion(ctx context.Context, userID, eventID, questionID uuid.UUID) error
+
+	// Sessions
+	GetSessions(ctx context.Context, userID, eventID uuid.UUID) ([]domain.EventSession, error)
+	CreateSession(ctx context.Context, userID, eventID uuid.UUID, req *domain.CreateSessionRequest) (*domain.EventSession, error)
+	UpdateSession(ctx context.Context, userID, eventID, sessionID uuid.UUID, req *domain.UpdateSessionRequest) (*domain.EventSession, error)
+	DeleteSession(ctx context.Context, userID, eventID, sessionID uuid.UUID) error
 }
 
 type eventService struct {
 	eventRepo    domain.EventRepository
 	templateRepo domain.TemplateRepository
 	mediaRepo    domain.MediaRepository
+	guestRepo    domain.GuestRepository
+	authz        Authorizer
 }
 
 func NewEventService(
 	eventRepo domain.EventRepository,
 	templateRepo domain.TemplateRepository,
 	mediaRepo domain.MediaRepository,
+	guestRepo domain.GuestRepository,
+	authz Authorizer,
 ) EventService {
 	return &eventService{
 		eventRepo:    eventRepo,
 		templateRepo: templateRepo,
 		mediaRepo:    mediaRepo,
+		guestRepo:    guestRepo,
+		authz:        authz,
 	}
 }
 
@@ -48,9 +79,9 @@ func (s *eventService) Create(ctx context.Context, userID uuid.UUID, req *domain
 		return nil, NewAppError(http.StatusBadRequest, "invalid template_id")
 	}
 
-	// Validate template exists
+	// Validate template exists and is offered to users
 	tmpl, err := s.templateRepo.FindByID(ctx, templateID)
-	if err != nil {
+	if err != nil || tmpl == nil || !tmpl.IsActive {
 		return nil, NewAppError(http.StatusNotFound, "template not found")
 	}
 
@@ -59,23 +90,32 @@ func (s *eventService) Create(ctx context.Context, userID uuid.UUID, req *domain
 		return nil, NewAppError(http.StatusBadRequest, "invalid event_date format, use RFC3339")
 	}
 
+	maxPartySize := 1
+	if req.MaxPartySize != nil {
+		maxPartySize = *req.MaxPartySize
+	}
+
 	// Generate unique slug
 	slug := s.generateUniqueSlug(ctx, req.Title)
 
 	now := time.Now()
 	event := &domain.Event{
-		ID:              uuid.New(),
-		UserID:          userID,
-		TemplateID:      templateID,
-		Title:           req.Title,
-		Slug:            slug,
-		EventDate:       eventDate,
-		LocationName:    req.LocationName,
-		LocationAddress: req.LocationAddress,
-		IsPublished:     false,
-		ViewCount:       0,
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:                uuid.New(),
+		UserID:            userID,
+		TemplateID:        templateID,
+		TemplateVersion:   tmpl.Version,
+		Title:             req.Title,
+		Slug:              slug,
+		EventDate:         eventDate,
+		LocationName:      req.LocationName,
+		LocationAddress:   req.LocationAddress,
+		MaxPartySize:      maxPartySize,
+		WishesAutoApprove: false,
+		GuestUploads:      domain.GuestUploadsOff,
+		IsPublished:       false,
+		ViewCount:         0,
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}
 
 	if err := s.eventRepo.Create(ctx, event); err != nil {
@@ -101,6 +141,8 @@ func (s *eventService) Create(ctx context.Context, userID uuid.UUID, req *domain
 			Content:           content,
 			IsVisible:         true,
 			SortOrder:         ts.SortOrder,
+			VisibleGroups:     pq.StringArray{},
+			VisibleGuestIDs:   pq.StringArray{},
 		})
 	}
 
@@ -117,6 +159,9 @@ func (s *eventService) Create(ctx context.Context, userID uuid.UUID, req *domain
 func (s *eventService) generateUniqueSlug(ctx context.Context, title string) string {
 	for {
 		slug := utils.GenerateSlug(title)
+		if utils.IsReservedSlug(slug) {
+			continue
+		}
 		exists, _ := s.eventRepo.SlugExists(ctx, slug)
 		if !exists {
 			return slug
@@ -124,15 +169,26 @@ func (s *eventService) generateUniqueSlug(ctx context.Context, title string) str
 	}
 }
 
-func (s *eventService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+// GetByID returns an event with the caller's role on it. Unpublished
+// events are only visible to the owner and collaborators.
+func (s *eventService) GetByID(ctx context.Context, userID, id uuid.UUID) (*domain.Event, error) {
 	event, err := s.eventRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, NewAppError(http.StatusNotFound, "event not found")
 	}
+
+	role, err := s.authz.Role(ctx, userID, event)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsPublished && role == "" {
+		return nil, NewAppError(http.StatusForbidden, "forbidden")
+	}
+	event.Role = role
 	return event, nil
 }
 
-func (s *eventService) GetBySlug(ctx context.Context, slug string) (*domain.PublicEventResponse, error) {
+func (s *eventService) GetBySlug(ctx context.Context, slug, guestCode string) (*domain.PublicEventResponse, error) {
 	event, err := s.eventRepo.FindBySlug(ctx, slug)
 	if err != nil || event == nil {
 		return nil, NewAppError(http.StatusNotFound, "event not found")
@@ -144,22 +200,86 @@ func (s *eventService) GetBySlug(ctx context.Context, slug string) (*domain.Publ
 	// Increment view count (fire and forget)
 	go s.eventRepo.IncrementViewCount(context.Background(), event.ID)
 
+	// Personalized link: only address guests invited to this event
+	var guest *domain.Guest
+	if guestCode != "" {
+		g, _ := s.guestRepo.FindByGuestCode(ctx, guestCode)
+		if g != nil && g.EventID == event.ID {
+			guest = g
+		}
+	}
+
 	theme, _ := s.eventRepo.FindThemeByEventID(ctx, event.ID)
-	sections, _ := s.eventRepo.FindSectionsByEventID(ctx, event.ID)
-	gallery, _ := s.mediaRepo.FindByEventID(ctx, event.ID)
+	allSections, _ := s.eventRepo.FindSectionsByEventID(ctx, event.ID)
+	allMedia, _ := s.mediaRepo.FindByEventID(ctx, event.ID)
+	albums, _ := s.mediaRepo.FindAlbumsByEventID(ctx, event.ID)
 	stats, _ := s.eventRepo.GetStats(ctx, event.ID)
+	questions, _ := s.eventRepo.FindQuestionsByEventID(ctx, event.ID)
+	allSessions, _ := s.eventRepo.FindSessionsByEventID(ctx, event.ID)
+
+	// Restricted sections and sessions are only sent to their audience, and
+	// the audience itself is never exposed publicly.
+	sections := make([]domain.EventSection, 0, len(allSections))
+	for _, section := range allSections {
+		if canSee(section.VisibleGroups, section.VisibleGuestIDs, guest) {
+			section.VisibleGroups, section.VisibleGuestIDs = nil, nil
+			sections = append(sections, section)
+		}
+	}
+	sessions := visibleSessions(allSessions, guest)
+	for i := range sessions {
+		sessions[i].VisibleGroups, sessions[i].VisibleGuestIDs = nil, nil
+	}
+
+	// Guest photos appear once the owner approves them, and photos only
+	// once processed, served from a variant instead of the original
+	gallery := make([]domain.Media, 0, len(allMedia))
+	for _, m := range allMedia {
+		if m.ApprovalStatus != domain.MediaApproved {
+			continue
+		}
+		if m.MediaType == domain.MediaTypeImage {
+			url, ok := publicImageURL(m)
+			if !ok {
+				continue
+			}
+			m.FileURL = url
+		}
+		gallery = append(gallery, m)
+	}
+	var cover *domain.Media
+	for i := range gallery {
+		if gallery[i].IsCover {
+			cover = &gallery[i]
+			break
+		}
+	}
+	music, _ := s.eventRepo.FindMusicByEventID(ctx, event.ID)
+	if music != nil {
+		for _, m := range gallery {
+			if m.ID == music.MediaID {
+				music.URL = m.FileURL
+			}
+		}
+	}
 
 	return &domain.PublicEventResponse{
-		Event:    event,
-		Theme:    theme,
-		Sections: sections,
-		Gallery:  gallery,
-		Stats:    stats,
+		Event:     event,
+		Theme:     theme,
+		Sections:  sections,
+		Gallery:   gallery,
+		Albums:    albums,
+		Cover:     cover,
+		Music:     music,
+		Stats:     stats,
+		Questions: questions,
+		Sessions:  sessions,
+		Guest:     guest,
 	}, nil
 }
 
 func (s *eventService) GetMyEvents(ctx context.Context, userID uuid.UUID) ([]domain.Event, error) {
-	events, err := s.eventRepo.FindByUserID(ctx, userID)
+	events, err := s.eventRepo.FindByMember(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
@@ -167,12 +287,9 @@ func (s *eventService) GetMyEvents(ctx context.Context, userID uuid.UUID) ([]dom
 }
 
 func (s *eventService) Update(ctx context.Context, userID, eventID uuid.UUID, req *domain.UpdateEventRequest) (*domain.Event, error) {
-	event, err := s.eventRepo.FindByID(ctx, eventID)
+	event, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit)
 	if err != nil {
-		return nil, NewAppError(http.StatusNotFound, "event not found")
-	}
-	if event.UserID != userID {
-		return nil, NewAppError(http.StatusForbidden, "forbidden")
+		return nil, err
 	}
 
 	if req.Title != nil {
@@ -191,6 +308,36 @@ func (s *eventService) Update(ctx context.Context, userID, eventID uuid.UUID, re
 	if req.LocationAddress != nil {
 		event.LocationAddress = req.LocationAddress
 	}
+	if req.MaxPartySize != nil {
+		event.MaxPartySize = *req.MaxPartySize
+	}
+	if req.RSVPDeadline != nil {
+		if *req.RSVPDeadline == "" {
+			event.RSVPDeadline = nil
+		} else {
+			t, err := time.Parse(time.RFC3339, *req.RSVPDeadline)
+			if err != nil {
+				return nil, NewAppError(http.StatusBadRequest, "invalid rsvp_deadline format")
+			}
+			event.RSVPDeadline = &t
+		}
+	}
+	if req.MaxAttendees != nil {
+		if *req.MaxAttendees == 0 {
+			event.MaxAttendees = nil
+		} else {
+			event.MaxAttendees = req.MaxAttendees
+		}
+	}
+	if req.WaitlistEnabled != nil {
+		event.WaitlistEnabled = *req.WaitlistEnabled
+	}
+	if req.WishesAutoApprove != nil {
+		event.WishesAutoApprove = *req.WishesAutoApprove
+	}
+	if req.GuestUploads != nil {
+		event.GuestUploads = domain.GuestUploadMode(*req.GuestUploads)
+	}
 	event.UpdatedAt = time.Now()
 
 	if err := s.eventRepo.Update(ctx, event); err != nil {
@@ -200,23 +347,16 @@ func (s *eventService) Update(ctx context.Context, userID, eventID uuid.UUID, re
 }
 
 func (s *eventService) Delete(ctx context.Context, userID, eventID uuid.UUID) error {
-	event, err := s.eventRepo.FindByID(ctx, eventID)
-	if err != nil {
-		return NewAppError(http.StatusNotFound, "event not found")
-	}
-	if event.UserID != userID {
-		return NewAppError(http.StatusForbidden, "forbidden")
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionManage); err != nil {
+		return err
 	}
 	return s.eventRepo.Delete(ctx, eventID)
 }
 
 func (s *eventService) Publish(ctx context.Context, userID, eventID uuid.UUID, publish bool) error {
-	event, err := s.eventRepo.FindByID(ctx, eventID)
+	event, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit)
 	if err != nil {
-		return NewAppError(http.StatusNotFound, "event not found")
-	}
-	if event.UserID != userID {
-		return NewAppError(http.StatusForbidden, "forbidden")
+		return err
 	}
 	event.IsPublished = publish
 	event.UpdatedAt = time.Now()
@@ -224,12 +364,8 @@ func (s *eventService) Publish(ctx context.Context, userID, eventID uuid.UUID, p
 }
 
 func (s *eventService) UpdateTheme(ctx context.Context, userID, eventID uuid.UUID, req *domain.UpdateThemeRequest) (*domain.EventTheme, error) {
-	event, err := s.eventRepo.FindByID(ctx, eventID)
-	if err != nil {
-		return nil, NewAppError(http.StatusNotFound, "event not found")
-	}
-	if event.UserID != userID {
-		return nil, NewAppError(http.StatusForbidden, "forbidden")
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
 	}
 
 	theme := &domain.EventTheme{
@@ -249,13 +385,77 @@ func (s *eventService) UpdateTheme(ctx context.Context, userID, eventID uuid.UUI
 	return theme, nil
 }
 
-func (s *eventService) UpdateSection(ctx context.Context, userID, eventID, sectionID uuid.UUID, req *domain.UpdateSectionRequest) (*domain.EventSection, error) {
-	event, err := s.eventRepo.FindByID(ctx, eventID)
+func (s *eventService) GetMusic(ctx context.Context, userID, eventID uuid.UUID) (*domain.EventMusic, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionView); err != nil {
+		return nil, err
+	}
+
+	music, err := s.eventRepo.FindMusicByEventID(ctx, eventID)
 	if err != nil {
-		return nil, NewAppError(http.StatusNotFound, "event not found")
+		return nil, fmt.Errorf("failed to get music: %w", err)
 	}
-	if event.UserID != userID {
-		return nil, NewAppError(http.StatusForbidden, "forbidden")
+	if music == nil {
+		return nil, NewAppError(http.StatusNotFound, "event has no background music")
+	}
+	if media, err := s.mediaRepo.FindByID(ctx, music.MediaID); err == nil {
+		music.URL = media.FileURL
+	}
+	return music, nil
+}
+
+func (s *eventService) UpdateMusic(ctx context.Context, userID, eventID uuid.UUID, req *domain.UpdateMusicRequest) (*domain.EventMusic, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	mediaID, err := uuid.Parse(req.MediaID)
+	if err != nil {
+		return nil, NewAppError(http.StatusBadRequest, "invalid media_id")
+	}
+	media, err := s.mediaRepo.FindByID(ctx, mediaID)
+	if err != nil || media.EventID != eventID {
+		return nil, NewAppError(http.StatusBadRequest, "media_id must be a media of this event")
+	}
+	if media.MediaType != domain.MediaTypeAudio {
+		return nil, NewAppError(http.StatusBadRequest, "background music must be an audio file")
+	}
+
+	music := &domain.EventMusic{
+		EventID:      eventID,
+		MediaID:      mediaID,
+		Autoplay:     true,
+		Loop:         true,
+		StartSeconds: req.StartSeconds,
+		UpdatedAt:    time.Now(),
+		URL:          media.FileURL,
+	}
+	if req.Autoplay != nil {
+		music.Autoplay = *req.Autoplay
+	}
+	if req.Loop != nil {
+		music.Loop = *req.Loop
+	}
+
+	if err := s.eventRepo.UpsertMusic(ctx, music); err != nil {
+		return nil, fmt.Errorf("failed to update music: %w", err)
+	}
+	return music, nil
+}
+
+func (s *eventService) DeleteMusic(ctx context.Context, userID, eventID uuid.UUID) error {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return err
+	}
+
+	if err := s.eventRepo.DeleteMusic(ctx, eventID); err != nil {
+		return fmt.Errorf("failed to delete music: %w", err)
+	}
+	return nil
+}
+
+func (s *eventService) UpdateSection(ctx context.Context, userID, eventID, sectionID uuid.UUID, req *domain.UpdateSectionRequest) (*domain.EventSection, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
 	}
 
 	// Get current sections to find the target
@@ -276,6 +476,9 @@ func (s *eventService) UpdateSection(ctx context.Context, userID, eventID, secti
 	}
 
 	if req.Content != nil {
+		if errs := ValidateSectionContent(target.Type, req.Content); len(errs) > 0 {
+			return nil, newSectionContentError(errs)
+		}
 		target.Content = req.Content
 	}
 	if req.IsVisible != nil {
@@ -284,9 +487,418 @@ func (s *eventService) UpdateSection(ctx context.Context, userID, eventID, secti
 	if req.SortOrder != nil {
 		target.SortOrder = *req.SortOrder
 	}
+	if req.VisibleGroups != nil || req.VisibleGuestIDs != nil {
+		groups, guestIDs, err := s.resolveAudience(ctx, eventID, req.VisibleGroups, req.VisibleGuestIDs)
+		if err != nil {
+			return nil, err
+		}
+		if req.VisibleGroups != nil {
+			target.VisibleGroups = groups
+		}
+		if req.VisibleGuestIDs != nil {
+			target.VisibleGuestIDs = guestIDs
+		}
+	}
 
 	if err := s.eventRepo.UpdateSection(ctx, target); err != nil {
 		return nil, fmt.Errorf("failed to update section: %w", err)
 	}
 	return target, nil
 }
+
+// GetStats returns RSVP stats for the owner, including aggregated answers
+// to custom questions and attendance per session.
+func (s *eventService) GetStats(ctx context.Context, userID, eventID uuid.UUID) (*domain.EventStats, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionView); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.eventRepo.GetStats(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	questions, err := s.eventRepo.FindQuestionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+	if len(questions) > 0 {
+		guests, err := s.guestRepo.FindByEventID(ctx, eventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get guests: %w", err)
+		}
+		stats.Answers = aggregateAnswers(questions, guests)
+	}
+
+	sessions, err := s.eventRepo.GetSessionStats(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session stats: %w", err)
+	}
+	stats.Sessions = sessions
+	return stats, nil
+}
+
+// RSVP questions
+
+func (s *eventService) GetQuestions(ctx context.Context, userID, eventID uuid.UUID) ([]domain.EventQuestion, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionView); err != nil {
+		return nil, err
+	}
+
+	questions, err := s.eventRepo.FindQuestionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+	return questions, nil
+}
+
+func (s *eventService) CreateQuestion(ctx context.Context, userID, eventID uuid.UUID, req *domain.CreateQuestionRequest) (*domain.EventQuestion, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	options, err := questionOptions(req.Type, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	question := &domain.EventQuestion{
+		ID:         uuid.New(),
+		EventID:    eventID,
+		Label:      req.Label,
+		Type:       req.Type,
+		Options:    options,
+		IsRequired: req.IsRequired,
+		SortOrder:  req.SortOrder,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.eventRepo.CreateQuestion(ctx, question); err != nil {
+		return nil, fmt.Errorf("failed to create question: %w", err)
+	}
+	return question, nil
+}
+
+func (s *eventService) UpdateQuestion(ctx context.Context, userID, eventID, questionID uuid.UUID, req *domain.UpdateQuestionRequest) (*domain.EventQuestion, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	questions, err := s.eventRepo.FindQuestionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find questions: %w", err)
+	}
+
+	var target *domain.EventQuestion
+	for i := range questions {
+		if questions[i].ID == questionID {
+			target = &questions[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, NewAppError(http.StatusNotFound, "question not found")
+	}
+
+	if req.Label != nil {
+		target.Label = *req.Label
+	}
+	if req.Options != nil {
+		options, err := questionOptions(target.Type, req.Options)
+		if err != nil {
+			return nil, err
+		}
+		target.Options = options
+	}
+	if req.IsRequired != nil {
+		target.IsRequired = *req.IsRequired
+	}
+	if req.SortOrder != nil {
+		target.SortOrder = *req.SortOrder
+	}
+
+	if err := s.eventRepo.UpdateQuestion(ctx, target); err != nil {
+		return nil, fmt.Errorf("failed to update question: %w", err)
+	}
+	return target, nil
+}
+
+func (s *eventService) DeleteQuestion(ctx context.Context, userID, eventID, questionID uuid.UUID) error {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return err
+	}
+	return s.eventRepo.DeleteQuestion(ctx, eventID, questionID)
+}
+
+// Sessions
+
+func (s *eventService) GetSessions(ctx context.Context, userID, eventID uuid.UUID) ([]domain.EventSession, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionView); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.eventRepo.FindSessionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+func (s *eventService) CreateSession(ctx context.Context, userID, eventID uuid.UUID, req *domain.CreateSessionRequest) (*domain.EventSession, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		return nil, NewAppError(http.StatusBadRequest, "invalid starts_at format")
+	}
+
+	session := &domain.EventSession{
+		ID:           uuid.New(),
+		EventID:      eventID,
+		Name:         req.Name,
+		StartsAt:     startsAt,
+		VenueName:    req.VenueName,
+		VenueAddress: req.VenueAddress,
+		MapURL:       req.MapURL,
+		SortOrder:    req.SortOrder,
+		CreatedAt:    time.Now(),
+	}
+	session.VisibleGroups, session.VisibleGuestIDs, err = s.resolveAudience(ctx, eventID, req.VisibleGroups, req.VisibleGuestIDs)
+	if err != nil {
+		return nil, err
+	}
+	if req.EndsAt != nil && *req.EndsAt != "" {
+		endsAt, err := time.Parse(time.RFC3339, *req.EndsAt)
+		if err != nil {
+			return nil, NewAppError(http.StatusBadRequest, "invalid ends_at format")
+		}
+		session.EndsAt = &endsAt
+	}
+	if err := validateSessionTimes(session); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.CreateSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return session, nil
+}
+
+func (s *eventService) UpdateSession(ctx context.Context, userID, eventID, sessionID uuid.UUID, req *domain.UpdateSessionRequest) (*domain.EventSession, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.eventRepo.FindSessionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+
+	var target *domain.EventSession
+	for i := range sessions {
+		if sessions[i].ID == sessionID {
+			target = &sessions[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, NewAppError(http.StatusNotFound, "session not found")
+	}
+
+	if req.Name != nil {
+		target.Name = *req.Name
+	}
+	if req.StartsAt != nil {
+		t, err := time.Parse(time.RFC3339, *req.StartsAt)
+		if err != nil {
+			return nil, NewAppError(http.StatusBadRequest, "invalid starts_at format")
+		}
+		target.StartsAt = t
+	}
+	if req.EndsAt != nil {
+		if *req.EndsAt == "" {
+			target.EndsAt = nil
+		} else {
+			t, err := time.Parse(time.RFC3339, *req.EndsAt)
+			if err != nil {
+				return nil, NewAppError(http.StatusBadRequest, "invalid ends_at format")
+			}
+			target.EndsAt = &t
+		}
+	}
+	if req.VenueName != nil {
+		target.VenueName = req.VenueName
+	}
+	if req.VenueAddress != nil {
+		target.VenueAddress = req.VenueAddress
+	}
+	if req.MapURL != nil {
+		target.MapURL = req.MapURL
+	}
+	if req.SortOrder != nil {
+		target.SortOrder = *req.SortOrder
+	}
+	if req.VisibleGroups != nil || req.VisibleGuestIDs != nil {
+		groups, guestIDs, err := s.resolveAudience(ctx, eventID, req.VisibleGroups, req.VisibleGuestIDs)
+		if err != nil {
+			return nil, err
+		}
+		if req.VisibleGroups != nil {
+			target.VisibleGroups = groups
+		}
+		if req.VisibleGuestIDs != nil {
+			target.VisibleGuestIDs = guestIDs
+		}
+	}
+	if err := validateSessionTimes(target); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.UpdateSession(ctx, target); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+	return target, nil
+}
+
+// DeleteSession removes a session; guests who picked it keep their other
+// sessions.
+func (s *eventService) DeleteSession(ctx context.Context, userID, eventID, sessionID uuid.UUID) error {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return err
+	}
+	return s.eventRepo.DeleteSession(ctx, eventID, sessionID)
+}
+
+// resolveAudience cleans up the groups of a restricted section or session
+// and checks that every guest ID belongs to the event.
+func (s *eventService) resolveAudience(ctx context.Context, eventID uuid.UUID, groups, guestIDs []string) (pq.StringArray, pq.StringArray, error) {
+	cleanGroups := pq.StringArray{}
+	for _, group := range groups {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			return nil, nil, NewAppError(http.StatusBadRequest, "visible_groups must not contain empty names")
+		}
+		if len(group) > 50 {
+			return nil, nil, NewAppError(http.StatusBadRequest, "visible_groups names must be at most 50 characters")
+		}
+		if !containsFold(cleanGroups, group) {
+			cleanGroups = append(cleanGroups, group)
+		}
+	}
+
+	cleanIDs := pq.StringArray{}
+	if len(guestIDs) > 0 {
+		guests, err := s.guestRepo.FindByEventID(ctx, eventID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get guests: %w", err)
+		}
+		known := make(map[uuid.UUID]bool, len(guests))
+		for _, g := range guests {
+			known[g.ID] = true
+		}
+		for _, raw := range guestIDs {
+			id, err := uuid.Parse(raw)
+			if err != nil || !known[id] {
+				return nil, nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("unknown guest %s", raw))
+			}
+			if !containsString(cleanIDs, id.String()) {
+				cleanIDs = append(cleanIDs, id.String())
+			}
+		}
+	}
+	return cleanGroups, cleanIDs, nil
+}
+
+// canSee reports whether a visitor may see content restricted to the given
+// groups / guest IDs. Anonymous visitors only see unrestricted content.
+func canSee(groups, guestIDs []string, guest *domain.Guest) bool {
+	if len(groups) == 0 && len(guestIDs) == 0 {
+		return true
+	}
+	if guest == nil {
+		return false
+	}
+	if containsString(guestIDs, guest.ID.String()) {
+		return true
+	}
+	return guest.GuestGroup != nil && containsFold(groups, *guest.GuestGroup)
+}
+
+func visibleSessions(sessions []domain.EventSession, guest *domain.Guest) []domain.EventSession {
+	visible := make([]domain.EventSession, 0, len(sessions))
+	for _, session := range sessions {
+		if canSee(session.VisibleGroups, session.VisibleGuestIDs, guest) {
+			visible = append(visible, session)
+		}
+	}
+	return visible
+}
+
+// publicImageURL returns the widest variant of a processed photo. Variants
+// are re-encoded without EXIF/GPS metadata, which the original may still
+// carry if it could not be stripped. Photos linked from elsewhere (no
+// storage key) are never processed and keep their own URL.
+func publicImageURL(m domain.Media) (string, bool) {
+	if m.ProcessingStatus != domain.MediaStatusReady {
+		return "", false
+	}
+	if m.StorageKey == nil {
+		return m.FileURL, m.FileURL != ""
+	}
+	if len(m.Variants) == 0 {
+		return "", false
+	}
+	var variants []domain.MediaVariant
+	if err := json.Unmarshal(m.Variants, &variants); err != nil {
+		return "", false
+	}
+	url, width := "", 0
+	for _, v := range variants {
+		if v.Width > width {
+			url, width = v.URL, v.Width
+		}
+	}
+	return url, url != ""
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSessionTimes(session *domain.EventSession) error {
+	if session.EndsAt != nil && !session.EndsAt.After(session.StartsAt) {
+		return NewAppError(http.StatusBadRequest, "ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// questionOptions validates the choices of a choice question; other types
+// carry no options.
+func questionOptions(qType domain.QuestionType, options []string) ([]string, error) {
+	if qType != domain.QuestionTypeSingleChoice && qType != domain.QuestionTypeMultiChoice {
+		return []string{}, nil
+	}
+
+	var cleaned []string
+	for _, opt := range options {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			return nil, NewAppError(http.StatusBadRequest, "options must not be empty")
+		}
+		if containsString(cleaned, opt) {
+			return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("duplicate option %q", opt))
+		}
+		cleaned = append(cleaned, opt)
+	}
+	if len(cleaned) == 0 {
+		return nil, NewAppError(http.StatusBadRequest, "choice questions need at least one option")
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/imaging"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/storage"
+)
+
+// imageVariants are the resized copies rendered for every photo, narrowest
+// first. Sizes wider than the original are skipped, except the thumbnail.
+var imageVariants = []struct {
+	Name  string
+	Width int
+}{
+	{"thumb", 320},
+	{"small", 640},
+	{"medium", 1280},
+	{"large", 1920},
+}
+
+const (
+	variantQuality  = 80
+	originalQuality = 90
+	processWorkers  = 2
+	processQueueLen = 100
+	// processScanEvery is how often media still pending are looked up,
+	// catching those that did not fit in the queue
+	processScanEvery = time.Minute
+)
+
+// MediaProcessor prepares uploaded photos in the background: it strips
+// EXIF/GPS metadata from the original, records its size and renders
+// resized JPEG variants for the gallery.
+type MediaProcessor interface {
+	// Enqueue schedules a pending media for processing. It never blocks;
+	// media that do not fit in the queue are picked up by a later scan.
+	Enqueue(mediaID uuid.UUID)
+	// Run processes media until ctx is done, starting with those left
+	// pending by a previous run and rescanning for pending media
+	// periodically.
+	Run(ctx context.Context)
+}
+
+type mediaProcessor struct {
+	mediaRepo domain.MediaRepository
+	storage   storage.Storage
+	queue     chan uuid.UUID
+
+	mu sync.Mutex
+	// queued holds media waiting in the queue or being processed, so a
+	// scan does not hand the same media to two workers
+	queued map[uuid.UUID]bool
+}
+
+func NewMediaProcessor(mediaRepo domain.MediaRepository, store storage.Storage) MediaProcessor {
+	return &mediaProcessor{
+		mediaRepo: mediaRepo,
+		storage:   store,
+		queue:     make(chan uuid.UUID, processQueueLen),
+		queued:    make(map[uuid.UUID]bool),
+	}
+}
+
+func (p *mediaProcessor) Enqueue(mediaID uuid.UUID) {
+	if !p.tryEnqueue(mediaID) {
+		log.Printf("media processing queue is full, deferring media %s", mediaID)
+	}
+}
+
+// tryEnqueue queues the media unless it is already queued. It reports
+// false when the queue is full.
+func (p *mediaProcessor) tryEnqueue(mediaID uuid.UUID) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.queued[mediaID] {
+		return true
+	}
+	select {
+	case p.queue <- mediaID:
+		p.queued[mediaID] = true
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *mediaProcessor) done(mediaID uuid.UUID) {
+	p.mu.Lock()
+	delete(p.queued, mediaID)
+	p.mu.Unlock()
+}
+
+func (p *mediaProcessor) Run(ctx context.Context) {
+	for i := 0; i < processWorkers; i++ {
+		go p.work(ctx)
+	}
+
+	ticker := time.NewTicker(processScanEvery)
+	defer ticker.Stop()
+	for {
+		p.scan(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scan queues pending media, oldest first, until the queue is full.
+func (p *mediaProcessor) scan(ctx context.Context) {
+	ids, err := p.mediaRepo.FindPendingIDs(ctx)
+	if err != nil {
+		log.Printf("failed to load pending media: %v", err)
+		return
+	}
+	for _, id := range ids {
+		if !p.tryEnqueue(id) {
+			return
+		}
+	}
+}
+
+func (p *mediaProcessor) work(ctx context.Context) {
+	for {
+		select {
+		case id := <-p.queue:
+			if err := p.process(ctx, id); err != nil {
+				log.Printf("failed to process media %s: %v", id, err)
+			}
+			p.done(id)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *mediaProcessor) process(ctx context.Context, id uuid.UUID) error {
+	media, err := p.mediaRepo.FindByID(ctx, id)
+	if err != nil {
+		// Deleted before it was processed
+		return nil
+	}
+	if media.ProcessingStatus != domain.MediaStatusPending || media.StorageKey == nil {
+		return nil
+	}
+
+	variants, err := p.render(ctx, media)
+	if err != nil {
+		p.deleteVariants(ctx, variants)
+		media.Variants = nil
+		media.ProcessingStatus = domain.MediaStatusFailed
+		if _, uerr := p.mediaRepo.UpdateProcessing(ctx, media); uerr != nil {
+			return uerr
+		}
+		return err
+	}
+
+	media.Variants, _ = json.Marshal(variants)
+	media.ProcessingStatus = domain.MediaStatusReady
+	ok, err := p.mediaRepo.UpdateProcessing(ctx, media)
+	if err != nil || !ok {
+		// Don't leave variants of a media deleted while we worked
+		p.deleteVariants(ctx, variants)
+	}
+	return err
+}
+
+func (p *mediaProcessor) deleteVariants(ctx context.Context, variants []domain.MediaVariant) {
+	for _, v := range variants {
+		p.storage.Delete(ctx, v.Key)
+	}
+}
+
+// render cleans the original in place, sets media's dimensions and
+// uploads the variants.
+func (p *mediaProcessor) render(ctx context.Context, media *domain.Media) ([]domain.MediaVariant, error) {
+	key := *media.StorageKey
+	rc, err := p.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read original: %w", err)
+	}
+
+	img, _, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rotated photos are re-encoded upright, since dropping EXIF also
+	// drops the orientation flag; others are stripped losslessly.
+	if orientation := imaging.Orientation(data); orientation > 1 {
+		img = imaging.Orient(img, orientation)
+		upright, err := imaging.EncodeJPEG(img, originalQuality)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.put(ctx, key, upright); err != nil {
+			return nil, err
+		}
+		media.SizeBytes = int64(len(upright))
+	} else if stripped, ok := imaging.StripMetadata(data); ok {
+		if err := p.put(ctx, key, stripped); err != nil {
+			return nil, err
+		}
+		media.SizeBytes = int64(len(stripped))
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	media.Width, media.Height = &width, &height
+
+	base := strings.TrimSuffix(key, path.Ext(key))
+	variants := make([]domain.MediaVariant, 0, len(imageVariants))
+	for _, size := range imageVariants {
+		w := size.Width
+		if w >= width {
+			if size.Name != "thumb" {
+				continue
+			}
+			w = width
+		}
+
+		resized := imaging.Resize(img, w)
+		encoded, err := imaging.EncodeJPEG(resized, variantQuality)
+		if err != nil {
+			return variants, err
+		}
+		vkey := fmt.Sprintf("%s_%s.jpg", base, size.Name)
+		if err := p.put(ctx, vkey, encoded); err != nil {
+			return variants, err
+		}
+		variants = append(variants, domain.MediaVariant{
+			Name:   size.Name,
+			Width:  resized.Bounds().Dx(),
+			Height: resized.Bounds().Dy(),
+			URL:    p.storage.URL(vkey),
+			Key:    vkey,
+		})
+	}
+	return variants, nil
+}
+
+func (p *mediaProcessor) put(ctx context.Context, key string, data []byte) error {
+	return p.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), http.DetectContentType(data))
+}
@@ -0,0 +1,673 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/event-invitation/internal/config"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	// downloadURLExpiry is how long a presigned media download link stays valid.
+	downloadURLExpiry = 15 * time.Minute
+	// uploadURLExpiry is how long a direct upload slot accepts the file.
+	uploadURLExpiry = time.Hour
+)
+
+type MediaService interface {
+	Upload(ctx context.Context, userID, eventID uuid.UUID, file *multipart.FileHeader) (*domain.Media, error)
+	// GetByEvent lists the event's media, optionally only those with the
+	// given approval status.
+	GetByEvent(ctx context.Context, userID, eventID uuid.UUID, status string) ([]domain.Media, error)
+	// CreateUpload reserves a slot for uploading a file straight to storage.
+	CreateUpload(ctx context.Context, userID, eventID uuid.UUID, req domain.CreateMediaUploadRequest) (*domain.MediaUpload, error)
+	// ConfirmUpload verifies the uploaded object and creates its media.
+	ConfirmUpload(ctx context.Context, userID, eventID, uploadID uuid.UUID) (*domain.Media, error)
+	// DownloadURL returns a short-lived link to the original file.
+	DownloadURL(ctx context.Context, userID, eventID, mediaID uuid.UUID) (string, error)
+	// Delete removes the media record and its file from storage.
+	Delete(ctx context.Context, userID, eventID, mediaID uuid.UUID) error
+
+	// Guest photos: shared from the public page, shown once approved
+	GuestUpload(ctx context.Context, slug string, req *domain.GuestUploadRequest, file *multipart.FileHeader) (*domain.Media, error)
+	Approve(ctx context.Context, userID, eventID, mediaID uuid.UUID) (*domain.Media, error)
+
+	// Gallery presentation
+	Update(ctx context.Context, userID, eventID, mediaID uuid.UUID, req *domain.UpdateMediaRequest) (*domain.Media, error)
+	Reorder(ctx context.Context, userID, eventID uuid.UUID, req *domain.ReorderMediaRequest) ([]domain.Media, error)
+	GetAlbums(ctx context.Context, userID, eventID uuid.UUID) ([]domain.MediaAlbum, error)
+	CreateAlbum(ctx context.Context, userID, eventID uuid.UUID, req *domain.CreateAlbumRequest) (*domain.MediaAlbum, error)
+	UpdateAlbum(ctx context.Context, userID, eventID, albumID uuid.UUID, req *domain.UpdateAlbumRequest) (*domain.MediaAlbum, error)
+	DeleteAlbum(ctx context.Context, userID, eventID, albumID uuid.UUID) error
+}
+
+type mediaService struct {
+	mediaRepo domain.MediaRepository
+	eventRepo domain.EventRepository
+	guestRepo domain.GuestRepository
+	authz     Authorizer
+	storage   storage.Storage
+	processor MediaProcessor
+	cfg       config.StorageConfig
+}
+
+func NewMediaService(
+	mediaRepo domain.MediaRepository,
+	eventRepo domain.EventRepository,
+	guestRepo domain.GuestRepository,
+	authz Authorizer,
+	store storage.Storage,
+	processor MediaProcessor,
+	cfg *config.Config,
+) MediaService {
+	return &mediaService{
+		mediaRepo: mediaRepo,
+		eventRepo: eventRepo,
+		guestRepo: guestRepo,
+		authz:     authz,
+		storage:   store,
+		processor: processor,
+		cfg:       cfg.Storage,
+	}
+}
+
+func (s *mediaService) Upload(ctx context.Context, userID, eventID uuid.UUID, file *multipart.FileHeader) (*domain.Media, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	key, kind, err := s.saveFile(ctx, eventID, file, false)
+	if err != nil {
+		return nil, err
+	}
+
+	media := s.newMedia(eventID, key, kind.mediaType, file.Size)
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		s.storage.Delete(ctx, key)
+		return nil, fmt.Errorf("failed to save media: %w", err)
+	}
+	s.enqueue(media)
+	return media, nil
+}
+
+func (s *mediaService) GuestUpload(ctx context.Context, slug string, req *domain.GuestUploadRequest, file *multipart.FileHeader) (*domain.Media, error) {
+	event, err := s.eventRepo.FindBySlug(ctx, slug)
+	if err != nil || event == nil || !event.IsPublished {
+		return nil, NewAppError(http.StatusNotFound, "event not found")
+	}
+	if event.GuestUploads != domain.GuestUploadsOpen && event.GuestUploads != domain.GuestUploadsGuests {
+		return nil, NewAppError(http.StatusForbidden, "this event does not accept photos from guests")
+	}
+
+	var guest *domain.Guest
+	if req.GuestCode != "" {
+		guest, err = s.guestRepo.FindByGuestCode(ctx, req.GuestCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find guest: %w", err)
+		}
+		if guest == nil || guest.EventID != event.ID {
+			return nil, NewAppError(http.StatusNotFound, "guest not found")
+		}
+	}
+	if guest == nil && event.GuestUploads == domain.GuestUploadsGuests {
+		return nil, NewAppError(http.StatusForbidden, "a guest code is required to share photos for this event")
+	}
+
+	key, kind, err := s.saveFile(ctx, event.ID, file, true)
+	if err != nil {
+		return nil, err
+	}
+
+	media := s.newMedia(event.ID, key, kind.mediaType, file.Size)
+	media.ApprovalStatus = domain.MediaPending
+	if req.Caption != "" {
+		media.Caption = &req.Caption
+	}
+	name := strings.TrimSpace(req.Name)
+	if guest != nil {
+		media.GuestID = &guest.ID
+		if name == "" {
+			name = guest.Name
+		}
+	}
+	if name != "" {
+		media.UploaderName = &name
+	}
+
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		s.storage.Delete(ctx, key)
+		return nil, fmt.Errorf("failed to save media: %w", err)
+	}
+	s.enqueue(media)
+	return media, nil
+}
+
+func (s *mediaService) Approve(ctx context.Context, userID, eventID, mediaID uuid.UUID) (*domain.Media, error) {
+	media, err := s.findEventMedia(ctx, userID, eventID, mediaID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+	if media.ApprovalStatus == domain.MediaApproved {
+		return media, nil
+	}
+	// Approved photos count against the event quota, not the guest one
+	if err := s.checkEventQuota(ctx, eventID, media.SizeBytes); err != nil {
+		return nil, err
+	}
+
+	if err := s.mediaRepo.UpdateApprovalStatus(ctx, media.ID, domain.MediaApproved); err != nil {
+		return nil, fmt.Errorf("failed to approve media: %w", err)
+	}
+	media.ApprovalStatus = domain.MediaApproved
+	return media, nil
+}
+
+// saveFile checks an uploaded file's type and size and stores it. The
+// type comes from the content, never from the file name. Guests may only
+// share photos, within their own quota.
+func (s *mediaService) saveFile(ctx context.Context, eventID uuid.UUID, file *multipart.FileHeader, fromGuest bool) (string, mediaKind, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", mediaKind{}, NewAppError(http.StatusBadRequest, "failed to read file")
+	}
+	defer src.Close()
+
+	head := make([]byte, storage.SniffLen)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", mediaKind{}, NewAppError(http.StatusBadRequest, "failed to read file")
+	}
+	contentType := storage.SniffContentType(head[:n])
+	kind, ok := allowedMediaTypes[contentType]
+	if !ok {
+		return "", mediaKind{}, unsupportedTypeError(contentType)
+	}
+	if fromGuest && kind.mediaType != domain.MediaTypeImage {
+		return "", mediaKind{}, NewAppError(http.StatusUnsupportedMediaType, "only photos (JPEG, PNG, GIF or WebP) can be shared")
+	}
+	if err := s.checkSize(ctx, eventID, kind.mediaType, file.Size, fromGuest); err != nil {
+		return "", mediaKind{}, err
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", mediaKind{}, NewAppError(http.StatusBadRequest, "failed to read file")
+	}
+
+	key := newMediaKey(eventID, kind.ext)
+	if err := s.storage.Put(ctx, key, src, file.Size, contentType); err != nil {
+		return "", mediaKind{}, fmt.Errorf("failed to save file: %w", err)
+	}
+	return key, kind, nil
+}
+
+func (s *mediaService) GetByEvent(ctx context.Context, userID, eventID uuid.UUID, status string) ([]domain.Media, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionView); err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case "", domain.MediaPending, domain.MediaApproved:
+	default:
+		return nil, NewAppError(http.StatusBadRequest, "invalid status")
+	}
+
+	media, err := s.mediaRepo.FindByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	if status == "" {
+		return media, nil
+	}
+
+	filtered := make([]domain.Media, 0, len(media))
+	for _, m := range media {
+		if m.ApprovalStatus == status {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *mediaService) CreateUpload(ctx context.Context, userID, eventID uuid.UUID, req domain.CreateMediaUploadRequest) (*domain.MediaUpload, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	contentType, _, err := mime.ParseMediaType(req.ContentType)
+	if err != nil {
+		return nil, NewAppError(http.StatusBadRequest, "invalid content_type")
+	}
+	if alias, ok := contentTypeAliases[contentType]; ok {
+		contentType = alias
+	}
+	kind, ok := allowedMediaTypes[contentType]
+	if !ok {
+		return nil, unsupportedTypeError(contentType)
+	}
+	if err := s.checkSize(ctx, eventID, kind.mediaType, req.Size, false); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	upload := &domain.MediaUpload{
+		ID:          uuid.New(),
+		EventID:     eventID,
+		StorageKey:  newMediaKey(eventID, kind.ext),
+		MediaType:   kind.mediaType,
+		ContentType: contentType,
+		SizeBytes:   req.Size,
+		CreatedBy:   userID,
+		ExpiresAt:   now.Add(uploadURLExpiry),
+		CreatedAt:   now,
+	}
+
+	uploadURL, headers, err := s.storage.PresignedPutURL(ctx, upload.StorageKey, upload.SizeBytes, uploadURLExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign upload url: %w", err)
+	}
+	upload.UploadURL = uploadURL
+	if len(headers) > 0 {
+		upload.UploadHeaders = make(map[string]string, len(headers))
+		for name := range headers {
+			upload.UploadHeaders[name] = headers.Get(name)
+		}
+	}
+	if err := s.mediaRepo.CreateUpload(ctx, upload); err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+	return upload, nil
+}
+
+func (s *mediaService) ConfirmUpload(ctx context.Context, userID, eventID, uploadID uuid.UUID) (*domain.Media, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	upload, err := s.mediaRepo.FindUploadByID(ctx, uploadID)
+	if err != nil || upload.EventID != eventID {
+		return nil, NewAppError(http.StatusNotFound, "upload not found")
+	}
+
+	info, err := s.storage.Stat(ctx, upload.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, NewAppError(http.StatusBadRequest, "file has not been uploaded yet")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check upload: %w", err)
+	}
+
+	// A file that does not match the slot is discarded along with the slot
+	kind, allowed := allowedMediaTypes[info.ContentType]
+	if info.Size != upload.SizeBytes || !allowed || kind.mediaType != upload.MediaType {
+		s.storage.Delete(ctx, upload.StorageKey)
+		s.mediaRepo.DeleteUpload(ctx, upload.ID)
+		if info.Size != upload.SizeBytes {
+			return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("uploaded file is %s, not the declared %s", formatSize(info.Size), formatSize(upload.SizeBytes)))
+		}
+		if !allowed {
+			return nil, unsupportedTypeError(info.ContentType)
+		}
+		return nil, NewAppError(http.StatusUnsupportedMediaType, fmt.Sprintf("uploaded file is %s, not the declared %s", info.ContentType, upload.ContentType))
+	}
+
+	media := s.newMedia(eventID, upload.StorageKey, upload.MediaType, info.Size)
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		return nil, fmt.Errorf("failed to save media: %w", err)
+	}
+	if err := s.mediaRepo.DeleteUpload(ctx, upload.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete upload: %w", err)
+	}
+	s.enqueue(media)
+	return media, nil
+}
+
+func (s *mediaService) DownloadURL(ctx context.Context, userID, eventID, mediaID uuid.UUID) (string, error) {
+	media, err := s.findEventMedia(ctx, userID, eventID, mediaID, ActionView)
+	if err != nil {
+		return "", err
+	}
+	if media.StorageKey == nil {
+		return media.FileURL, nil
+	}
+
+	url, err := s.storage.PresignedGetURL(ctx, *media.StorageKey, downloadURLExpiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign download url: %w", err)
+	}
+	return url, nil
+}
+
+func (s *mediaService) Delete(ctx context.Context, userID, eventID, mediaID uuid.UUID) error {
+	media, err := s.findEventMedia(ctx, userID, eventID, mediaID, ActionEdit)
+	if err != nil {
+		return err
+	}
+
+	// Remove the files first so a failure leaves the record to retry with
+	var variants []domain.MediaVariant
+	if len(media.Variants) > 0 {
+		json.Unmarshal(media.Variants, &variants)
+	}
+	for _, v := range variants {
+		if err := s.storage.Delete(ctx, v.Key); err != nil {
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
+	if media.StorageKey != nil {
+		if err := s.storage.Delete(ctx, *media.StorageKey); err != nil {
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
+
+	if err := s.mediaRepo.Delete(ctx, media.ID); err != nil {
+		return fmt.Errorf("failed to delete media: %w", err)
+	}
+	return nil
+}
+
+func (s *mediaService) Update(ctx context.Context, userID, eventID, mediaID uuid.UUID, req *domain.UpdateMediaRequest) (*domain.Media, error) {
+	media, err := s.findEventMedia(ctx, userID, eventID, mediaID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Caption != nil {
+		media.Caption = req.Caption
+	}
+	if req.AltText != nil {
+		media.AltText = req.AltText
+	}
+	if req.AlbumID != nil {
+		if *req.AlbumID == "" {
+			media.AlbumID = nil
+		} else {
+			album, err := s.findAlbum(ctx, eventID, *req.AlbumID)
+			if err != nil {
+				return nil, err
+			}
+			media.AlbumID = &album.ID
+		}
+	}
+
+	if err := s.mediaRepo.Update(ctx, media); err != nil {
+		return nil, fmt.Errorf("failed to update media: %w", err)
+	}
+	if req.IsCover != nil && *req.IsCover != media.IsCover {
+		var cover *uuid.UUID
+		if *req.IsCover {
+			cover = &media.ID
+		}
+		if err := s.mediaRepo.SetCover(ctx, eventID, cover); err != nil {
+			return nil, fmt.Errorf("failed to set cover: %w", err)
+		}
+		media.IsCover = *req.IsCover
+	}
+	return media, nil
+}
+
+func (s *mediaService) Reorder(ctx context.Context, userID, eventID uuid.UUID, req *domain.ReorderMediaRequest) ([]domain.Media, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	media, err := s.mediaRepo.FindByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	known := make(map[uuid.UUID]bool, len(media))
+	for _, m := range media {
+		known[m.ID] = true
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.MediaIDs))
+	seen := make(map[uuid.UUID]bool, len(req.MediaIDs))
+	for _, raw := range req.MediaIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil || !known[id] {
+			return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("media %s does not belong to this event", raw))
+		}
+		if seen[id] {
+			return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("media %s is listed twice", raw))
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if err := s.mediaRepo.Reorder(ctx, eventID, ids); err != nil {
+		return nil, fmt.Errorf("failed to reorder media: %w", err)
+	}
+	return s.mediaRepo.FindByEventID(ctx, eventID)
+}
+
+func (s *mediaService) GetAlbums(ctx context.Context, userID, eventID uuid.UUID) ([]domain.MediaAlbum, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionView); err != nil {
+		return nil, err
+	}
+
+	albums, err := s.mediaRepo.FindAlbumsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get albums: %w", err)
+	}
+	return albums, nil
+}
+
+func (s *mediaService) CreateAlbum(ctx context.Context, userID, eventID uuid.UUID, req *domain.CreateAlbumRequest) (*domain.MediaAlbum, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	album := &domain.MediaAlbum{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		Title:     req.Title,
+		SortOrder: req.SortOrder,
+		CreatedAt: time.Now(),
+	}
+	if err := s.mediaRepo.CreateAlbum(ctx, album); err != nil {
+		return nil, fmt.Errorf("failed to create album: %w", err)
+	}
+	return album, nil
+}
+
+func (s *mediaService) UpdateAlbum(ctx context.Context, userID, eventID, albumID uuid.UUID, req *domain.UpdateAlbumRequest) (*domain.MediaAlbum, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	album, err := s.findAlbum(ctx, eventID, albumID.String())
+	if err != nil {
+		return nil, err
+	}
+	if req.Title != nil {
+		album.Title = *req.Title
+	}
+	if req.SortOrder != nil {
+		album.SortOrder = *req.SortOrder
+	}
+
+	if err := s.mediaRepo.UpdateAlbum(ctx, album); err != nil {
+		return nil, fmt.Errorf("failed to update album: %w", err)
+	}
+	return album, nil
+}
+
+func (s *mediaService) DeleteAlbum(ctx context.Context, userID, eventID, albumID uuid.UUID) error {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit); err != nil {
+		return err
+	}
+
+	if _, err := s.findAlbum(ctx, eventID, albumID.String()); err != nil {
+		return err
+	}
+	if err := s.mediaRepo.DeleteAlbum(ctx, eventID, albumID); err != nil {
+		return fmt.Errorf("failed to delete album: %w", err)
+	}
+	return nil
+}
+
+func (s *mediaService) findAlbum(ctx context.Context, eventID uuid.UUID, albumID string) (*domain.MediaAlbum, error) {
+	albums, err := s.mediaRepo.FindAlbumsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find albums: %w", err)
+	}
+	for i := range albums {
+		if albums[i].ID.String() == albumID {
+			return &albums[i], nil
+		}
+	}
+	return nil, NewAppError(http.StatusNotFound, "album not found")
+}
+
+func (s *mediaService) findEventMedia(ctx context.Context, userID, eventID, mediaID uuid.UUID, action Action) (*domain.Media, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, action); err != nil {
+		return nil, err
+	}
+
+	media, err := s.mediaRepo.FindByID(ctx, mediaID)
+	if err != nil || media.EventID != eventID {
+		return nil, NewAppError(http.StatusNotFound, "media not found")
+	}
+	return media, nil
+}
+
+// newMedia builds the record for a stored file. Images wait for the
+// processor, which strips their metadata and renders the variants.
+func (s *mediaService) newMedia(eventID uuid.UUID, key string, mediaType domain.MediaType, size int64) *domain.Media {
+	status := domain.MediaStatusReady
+	if mediaType == domain.MediaTypeImage {
+		status = domain.MediaStatusPending
+	}
+	return &domain.Media{
+		ID:               uuid.New(),
+		EventID:          eventID,
+		FileURL:          s.storage.URL(key),
+		MediaType:        mediaType,
+		StorageKey:       &key,
+		SizeBytes:        size,
+		ProcessingStatus: status,
+		ApprovalStatus:   domain.MediaApproved,
+		CreatedAt:        time.Now(),
+	}
+}
+
+func (s *mediaService) enqueue(media *domain.Media) {
+	if media.ProcessingStatus == domain.MediaStatusPending {
+		s.processor.Enqueue(media.ID)
+	}
+}
+
+// newMediaKey returns a fresh storage key for an event's media file.
+func newMediaKey(eventID uuid.UUID, ext string) string {
+	filename := fmt.Sprintf("%d-%s%s", time.Now().UnixNano(), uuid.New().String()[:8], ext)
+	return path.Join("events", eventID.String(), filename)
+}
+
+// mediaKind is an allowed content type with the media type it is stored
+// as and the extension given to its file.
+type mediaKind struct {
+	mediaType domain.MediaType
+	ext       string
+}
+
+// allowedMediaTypes lists the accepted content types, as detected by
+// storage.SniffContentType.
+var allowedMediaTypes = map[string]mediaKind{
+	"image/jpeg":      {domain.MediaTypeImage, ".jpg"},
+	"image/png":       {domain.MediaTypeImage, ".png"},
+	"image/gif":       {domain.MediaTypeImage, ".gif"},
+	"image/webp":      {domain.MediaTypeImage, ".webp"},
+	"video/mp4":       {domain.MediaTypeVideo, ".mp4"},
+	"video/webm":      {domain.MediaTypeVideo, ".webm"},
+	"video/quicktime": {domain.MediaTypeVideo, ".mov"},
+	"audio/mpeg":      {domain.MediaTypeAudio, ".mp3"},
+	"audio/wave":      {domain.MediaTypeAudio, ".wav"},
+	"application/ogg": {domain.MediaTypeAudio, ".ogg"},
+}
+
+// contentTypeAliases maps other names browsers declare to the names in
+// allowedMediaTypes.
+var contentTypeAliases = map[string]string{
+	"image/jpg":   "image/jpeg",
+	"audio/mp3":   "audio/mpeg",
+	"audio/wav":   "audio/wave",
+	"audio/x-wav": "audio/wave",
+	"audio/ogg":   "application/ogg",
+}
+
+func unsupportedTypeError(contentType string) error {
+	return NewAppError(http.StatusUnsupportedMediaType, fmt.Sprintf(
+		"file type %s is not allowed; upload JPEG, PNG, GIF or WebP images, MP4, WebM or MOV videos, or MP3, WAV or OGG audio",
+		contentType,
+	))
+}
+
+// checkSize enforces the per-type size limit and the event's storage
+// quota, which also counts upload slots still in progress. Guest photos
+// awaiting approval have a separate quota, so anonymous uploads cannot use
+// up the owner's.
+func (s *mediaService) checkSize(ctx context.Context, eventID uuid.UUID, mediaType domain.MediaType, size int64, fromGuest bool) error {
+	limit := s.cfg.MaxImageSize
+	switch mediaType {
+	case domain.MediaTypeVideo:
+		limit = s.cfg.MaxVideoSize
+	case domain.MediaTypeAudio:
+		limit = s.cfg.MaxAudioSize
+	}
+	if size > limit {
+		return NewAppError(http.StatusRequestEntityTooLarge, fmt.Sprintf(
+			"%s files must be at most %s, this file is %s", mediaType, formatSize(limit), formatSize(size),
+		))
+	}
+
+	if fromGuest {
+		return s.checkGuestQuota(ctx, eventID, size)
+	}
+	return s.checkEventQuota(ctx, eventID, size)
+}
+
+func (s *mediaService) checkEventQuota(ctx context.Context, eventID uuid.UUID, size int64) error {
+	if s.cfg.EventQuota <= 0 {
+		return nil
+	}
+	used, err := s.mediaRepo.StorageUsedByEvent(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to check storage quota: %w", err)
+	}
+	if used+size > s.cfg.EventQuota {
+		return NewAppError(http.StatusRequestEntityTooLarge, fmt.Sprintf(
+			"event storage quota exceeded: %s of %s used, this file is %s", formatSize(used), formatSize(s.cfg.EventQuota), formatSize(size),
+		))
+	}
+	return nil
+}
+
+func (s *mediaService) checkGuestQuota(ctx context.Context, eventID uuid.UUID, size int64) error {
+	if s.cfg.GuestQuota <= 0 {
+		return nil
+	}
+	pending, err := s.mediaRepo.PendingStorageByEvent(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to check guest upload quota: %w", err)
+	}
+	if pending+size > s.cfg.GuestQuota {
+		return NewAppError(http.StatusRequestEntityTooLarge, "this event is not accepting more photos until the owner reviews the pending ones")
+	}
+	return nil
+}
+
+// formatSize renders a byte count in MB, e.g. "12.5 MB".
+func formatSize(bytes int64) string {
+	return strconv.FormatFloat(float64(bytes)/(1<<20), 'f', 1, 64) + " MB"
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/cache"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/imaging"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/storage"
+)
+
+const (
+	previewTTL     = 24 * time.Hour
+	previewQuality = 85
+	// maxCoverBytes caps how much of the cover photo is read for the
+	// preview background
+	maxCoverBytes = 20 << 20
+)
+
+// Colors used when the event theme sets none (or not as hex)
+var (
+	defaultPrimaryColor   = color.RGBA{0x8b, 0x5e, 0x3c, 0xff}
+	defaultSecondaryColor = color.RGBA{0xf7, 0xf1, 0xea, 0xff}
+)
+
+// PreviewService renders the social share image of published events.
+type PreviewService interface {
+	GetBySlug(ctx context.Context, slug string) (*domain.EventPreview, error)
+}
+
+type previewService struct {
+	eventRepo domain.EventRepository
+	mediaRepo domain.MediaRepository
+	storage   storage.Storage
+	cache     cache.Cache
+}
+
+func NewPreviewService(eventRepo domain.EventRepository, mediaRepo domain.MediaRepository, store storage.Storage, c cache.Cache) PreviewService {
+	return &previewService{eventRepo: eventRepo, mediaRepo: mediaRepo, storage: store, cache: c}
+}
+
+// GetBySlug returns the preview image of an event. Images are cached by
+// PreviewVersion, so editing the event, its theme or its cover photo
+// renders a new one; stale entries expire on their own.
+func (s *previewService) GetBySlug(ctx context.Context, slug string) (*domain.EventPreview, error) {
+	event, err := s.eventRepo.FindBySlug(ctx, slug)
+	if err != nil || event == nil || !event.IsPublished {
+		return nil, NewAppError(http.StatusNotFound, "event not found")
+	}
+	theme, _ := s.eventRepo.FindThemeByEventID(ctx, event.ID)
+	media, _ := s.mediaRepo.FindByEventID(ctx, event.ID)
+	cover := findCover(media)
+
+	version := PreviewVersion(event, theme, cover)
+	key := fmt.Sprintf("preview:%s:%s", event.ID, version)
+	if data, ok, err := s.cache.Get(ctx, key); err == nil && ok {
+		return &domain.EventPreview{Image: data, Version: version}, nil
+	}
+
+	card := imaging.Card{
+		Title:      event.Title,
+		Lines:      previewLines(event),
+		Primary:    defaultPrimaryColor,
+		Secondary:  defaultSecondaryColor,
+		Background: s.loadCover(ctx, cover),
+	}
+	if theme != nil {
+		if c, ok := imaging.ParseHexColor(derefString(theme.PrimaryColor)); ok {
+			card.Primary = c
+		}
+		if c, ok := imaging.ParseHexColor(derefString(theme.SecondaryColor)); ok {
+			card.Secondary = c
+		}
+	}
+	img, err := imaging.RenderCard(card)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render preview: %w", err)
+	}
+	data, err := imaging.EncodeJPEG(img, previewQuality)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preview: %w", err)
+	}
+
+	if err := s.cache.Set(ctx, key, data, previewTTL); err != nil {
+		log.Printf("preview %s: %v", event.ID, err)
+	}
+	return &domain.EventPreview{Image: data, Version: version}, nil
+}
+
+// loadCover decodes the cover photo, preferring its medium variant. The
+// preview falls back to the theme color when it cannot be read.
+func (s *previewService) loadCover(ctx context.Context, cover *domain.Media) image.Image {
+	if cover == nil || cover.StorageKey == nil {
+		return nil
+	}
+	key := *cover.StorageKey
+	var variants []domain.MediaVariant
+	if len(cover.Variants) > 0 {
+		_ = json.Unmarshal(cover.Variants, &variants)
+	}
+	for _, v := range variants {
+		if v.Name == "medium" {
+			key = v.Key
+		}
+	}
+
+	rc, err := s.storage.Get(ctx, key)
+	if err != nil {
+		log.Printf("preview cover %s: %v", cover.ID, err)
+		return nil
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxCoverBytes))
+	if err != nil {
+		log.Printf("preview cover %s: %v", cover.ID, err)
+		return nil
+	}
+	img, _, err := imaging.Decode(data)
+	if err != nil {
+		log.Printf("preview cover %s: %v", cover.ID, err)
+		return nil
+	}
+	return img
+}
+
+// PreviewVersion fingerprints everything drawn on the preview image. Pages
+// add it to the image URL so link previews refresh after an edit.
+func PreviewVersion(event *domain.Event, theme *domain.EventTheme, cover *domain.Media) string {
+	parts := []string{event.Title, event.EventDate.Format(time.RFC3339), derefString(event.LocationName)}
+	if theme != nil {
+		parts = append(parts, derefString(theme.PrimaryColor), derefString(theme.SecondaryColor))
+	}
+	if cover != nil {
+		parts = append(parts, cover.ID.String(), string(cover.Variants))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// PreviewDescription is the date and venue line shown in link previews.
+func PreviewDescription(event *domain.Event) string {
+	return strings.Join(previewLines(event), " · ")
+}
+
+func previewLines(event *domain.Event) []string {
+	lines := []string{event.EventDate.Format("Monday, 2 January 2006")}
+	if event.LocationName != nil && *event.LocationName != "" {
+		lines = append(lines, *event.LocationName)
+	}
+	return lines
+}
+
+// findCover returns the approved cover photo among media, if any.
+func findCover(media []domain.Media) *domain.Media {
+	for i := range media {
+		m := &media[i]
+		if m.IsCover && m.MediaType == "image" && m.ApprovalStatus == domain.MediaApproved {
+			return m
+		}
+	}
+	return nil
+}
@@ -2,26 +2,53 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/utils"
 )
 
 type RSVPService interface {
 	Submit(ctx context.Context, eventID uuid.UUID, req *domain.RSVPRequest) (*domain.Guest, error)
+	GetByCode(ctx context.Context, code string) (*domain.Guest, error)
+	UpdateByCode(ctx context.Context, code string, req *domain.UpdateRSVPRequest) (*domain.Guest, error)
 	GetGuests(ctx context.Context, userID, eventID uuid.UUID) ([]domain.Guest, error)
+	CreateGuest(ctx context.Context, userID, eventID uuid.UUID, req *domain.CreateGuestRequest) (*domain.Guest, error)
+	UpdateGuest(ctx context.Context, userID, eventID, guestID uuid.UUID, req *domain.UpdateGuestRequest) (*domain.Guest, error)
+	DeleteGuest(ctx context.Context, userID, eventID, guestID uuid.UUID) error
+	PromoteGuest(ctx context.Context, userID, eventID, guestID uuid.UUID) (*domain.Guest, error)
+	GetGuestHistory(ctx context.Context, userID, eventID, guestID uuid.UUID) ([]domain.GuestRSVPChange, error)
+	ImportGuests(ctx context.Context, userID, eventID uuid.UUID, filename string, file io.Reader) (*domain.ImportGuestsResult, error)
+	ExportGuests(ctx context.Context, userID, eventID uuid.UUID) (*domain.GuestExport, error)
 }
 
+// maxImportRows caps a single guest list upload (header excluded).
+const maxImportRows = 5000
+
 type rsvpService struct {
 	guestRepo domain.GuestRepository
 	eventRepo domain.EventRepository
+	authz     Authorizer
+	validate  *validator.Validate
 }
 
-func NewRSVPService(guestRepo domain.GuestRepository, eventRepo domain.EventRepository) RSVPService {
-	return &rsvpService{guestRepo: guestRepo, eventRepo: eventRepo}
+func NewRSVPService(guestRepo domain.GuestRepository, eventRepo domain.EventRepository, authz Authorizer) RSVPService {
+	// Reuse the gin binding tags so imported rows follow the RSVP rules
+	validate := validator.New()
+	validate.SetTagName("binding")
+
+	return &rsvpService{guestRepo: guestRepo, eventRepo: eventRepo, authz: authz, validate: validate}
 }
 
 func (s *rsvpService) Submit(ctx context.Context, eventID uuid.UUID, req *domain.RSVPRequest) (*domain.Guest, error) {
@@ -29,34 +56,510 @@ func (s *rsvpService) Submit(ctx context.Context, eventID uuid.UUID, req *domain
 	if err != nil || event == nil {
 		return nil, NewAppError(http.StatusNotFound, "event not found")
 	}
-	if !event.IsPublished {
-		return nil, NewAppError(http.StatusBadRequest, "event is not published")
+	if err := checkRSVPOpen(event); err != nil {
+		return nil, err
+	}
+
+	// Invited guests answer on their pre-registered record
+	if req.GuestCode != nil && *req.GuestCode != "" {
+		guest, err := s.guestRepo.FindByGuestCode(ctx, *req.GuestCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find guest: %w", err)
+		}
+		if guest == nil || guest.EventID != eventID {
+			return nil, NewAppError(http.StatusNotFound, "guest not found")
+		}
+
+		in := rsvpAnswer{
+			Status:     req.Status,
+			Attendees:  req.Attendees,
+			Message:    req.Message,
+			Answers:    req.Answers,
+			SessionIDs: req.SessionIDs,
+		}
+		if err := s.answer(ctx, event, guest, in); err != nil {
+			return nil, err
+		}
+		return guest, nil
+	}
+
+	attendees, err := resolveAttendees(req.Status, req.Attendees, event.MaxPartySize)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := s.eventRepo.FindQuestionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+	answers, err := normalizeAnswers(questions, req.Answers, req.Status == domain.RSVPStatusYes)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.eventRepo.FindSessionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	sessionIDs, err := resolveSessions(visibleSessions(sessions, nil), req.Status, req.SessionIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The code lets the guest come back and change their answer
+	code, err := s.generateUniqueGuestCode(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	guest := &domain.Guest{
-		ID:         uuid.New(),
-		EventID:    eventID,
-		Name:       req.Name,
-		Phone:      req.Phone,
-		Message:    req.Message,
-		RSVPStatus: req.Status,
-		GuestCode:  req.GuestCode,
-		CreatedAt:  time.Now(),
+		ID:            uuid.New(),
+		EventID:       eventID,
+		Name:          req.Name,
+		Phone:         req.Phone,
+		Message:       req.Message,
+		RSVPStatus:    req.Status,
+		GuestCode:     &code,
+		PartySize:     event.MaxPartySize,
+		Attendees:     attendees,
+		Answers:       answers,
+		SessionIDs:    sessionIDs,
+		MessageStatus: newMessageStatus(event),
+		CreatedAt:     time.Now(),
 	}
 
-	if err := s.guestRepo.Create(ctx, guest); err != nil {
-		return nil, fmt.Errorf("failed to save rsvp: %w", err)
+	w := &domain.RSVPWrite{Guest: guest, Create: true, Decide: capacityCheck(event, nil, guest.RSVPStatus, attendees)}
+	if err := s.guestRepo.SaveRSVP(ctx, w); err != nil {
+		return nil, rsvpSaveError(err)
 	}
 	return guest, nil
 }
 
-func (s *rsvpService) GetGuests(ctx context.Context, userID, eventID uuid.UUID) ([]domain.Guest, error) {
-	event, err := s.eventRepo.FindByID(ctx, eventID)
+// GetByCode returns the guest's own RSVP for the edit page.
+func (s *rsvpService) GetByCode(ctx context.Context, code string) (*domain.Guest, error) {
+	guest, err := s.guestRepo.FindByGuestCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find guest: %w", err)
+	}
+	if guest == nil {
+		return nil, NewAppError(http.StatusNotFound, "guest not found")
+	}
+	return guest, nil
+}
+
+// UpdateByCode lets a guest change or cancel their answer.
+func (s *rsvpService) UpdateByCode(ctx context.Context, code string, req *domain.UpdateRSVPRequest) (*domain.Guest, error) {
+	guest, err := s.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.eventRepo.FindByID(ctx, guest.EventID)
 	if err != nil {
 		return nil, NewAppError(http.StatusNotFound, "event not found")
 	}
-	if event.UserID != userID {
-		return nil, NewAppError(http.StatusForbidden, "forbidden")
+	if err := checkRSVPOpen(event); err != nil {
+		return nil, err
+	}
+
+	in := rsvpAnswer{
+		Status:     guest.RSVPStatus,
+		Attendees:  req.Attendees,
+		Message:    guest.Message,
+		Answers:    req.Answers,
+		SessionIDs: req.SessionIDs,
+	}
+	if req.Status != nil {
+		in.Status = *req.Status
+	}
+	if in.Attendees == nil && guest.Attendees > 0 {
+		in.Attendees = &guest.Attendees
+	}
+	if req.Message != nil {
+		in.Message = req.Message
+	}
+	if req.Phone != nil {
+		in.Phone = req.Phone
+	}
+
+	if err := s.answer(ctx, event, guest, in); err != nil {
+		return nil, err
+	}
+	return guest, nil
+}
+
+// rsvpAnswer is what a guest submits for their existing record. Nil
+// Phone, Answers or SessionIDs keep the ones already stored.
+type rsvpAnswer struct {
+	Status     domain.RSVPStatus
+	Attendees  *int
+	Phone      *string
+	Message    *string
+	Answers    map[string]interface{}
+	SessionIDs []string
+}
+
+// answer applies a guest's answer to their existing record, enforcing party
+// size, capacity, custom questions and session choices, and logs
+// status/headcount changes for the owner.
+func (s *rsvpService) answer(ctx context.Context, event *domain.Event, guest *domain.Guest, in rsvpAnswer) error {
+	status, message := in.Status, in.Message
+	attendees, err := resolveAttendees(status, in.Attendees, guest.PartySize)
+	if err != nil {
+		return err
+	}
+
+	questions, err := s.eventRepo.FindQuestionsByEventID(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get questions: %w", err)
+	}
+	answers := in.Answers
+	if answers == nil {
+		answers = decodeAnswers(guest.Answers)
+	}
+	normalized, err := normalizeAnswers(questions, answers, status == domain.RSVPStatusYes)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := s.eventRepo.FindSessionsByEventID(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %w", err)
+	}
+	sessionIDs, err := resolveSessions(visibleSessions(sessions, guest), status, in.SessionIDs, guest.SessionIDs)
+	if err != nil {
+		return err
+	}
+
+	updated := *guest
+	updated.RSVPStatus, updated.Attendees, updated.Message = status, attendees, message
+	updated.Answers, updated.SessionIDs = normalized, sessionIDs
+	if in.Phone != nil {
+		updated.Phone = in.Phone
+	}
+	// An edited wish goes back through moderation
+	if derefString(message) != derefString(guest.Message) {
+		updated.MessageStatus = newMessageStatus(event)
+	}
+
+	// Everything, including the history entry, is saved at once
+	w := &domain.RSVPWrite{
+		Guest:  &updated,
+		Decide: capacityCheck(event, guest, status, attendees),
+		Change: newChange(guest, domain.RSVPChangeSourceGuest),
+	}
+	if err := s.guestRepo.SaveRSVP(ctx, w); err != nil {
+		return rsvpSaveError(err)
+	}
+	*guest = updated
+	return nil
+}
+
+// newChange starts the history entry of a change to the guest's answer.
+func newChange(guest *domain.Guest, source domain.RSVPChangeSource) *domain.GuestRSVPChange {
+	return &domain.GuestRSVPChange{
+		ID:            uuid.New(),
+		GuestID:       guest.ID,
+		FromStatus:    guest.RSVPStatus,
+		FromAttendees: guest.Attendees,
+		Source:        source,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// newMessageStatus is the moderation status of a freshly written wish.
+func newMessageStatus(event *domain.Event) domain.MessageStatus {
+	if event.WishesAutoApprove {
+		return domain.MessageStatusApproved
+	}
+	return domain.MessageStatusPending
+}
+
+func checkRSVPOpen(event *domain.Event) error {
+	if !event.IsPublished {
+		return NewAppError(http.StatusBadRequest, "event is not published")
+	}
+	if event.RSVPDeadline != nil && time.Now().After(*event.RSVPDeadline) {
+		return NewAppError(http.StatusForbidden, "rsvp is closed: the deadline has passed")
+	}
+	return nil
+}
+
+// capacityCheck checks a "yes" against the event's max attendees, given
+// the headcount other guests have confirmed. When the event is full the
+// guest is waitlisted if enabled, otherwise rejected. SaveRSVP runs it
+// under the event lock.
+//
+// from is the guest's current answer (nil for a new guest). A guest who is
+// already confirmed keeps their seats, so only a new "yes" or a larger
+// headcount is checked.
+func capacityCheck(event *domain.Event, from *domain.Guest, status domain.RSVPStatus, attendees int) func(headcount int) (domain.RSVPStatus, error) {
+	if status != domain.RSVPStatusYes || event.MaxAttendees == nil {
+		return nil
+	}
+	if from != nil && from.RSVPStatus == domain.RSVPStatusYes && attendees <= from.Attendees {
+		return nil
+	}
+	max, waitlist := *event.MaxAttendees, event.WaitlistEnabled
+	return func(headcount int) (domain.RSVPStatus, error) {
+		remaining := max - headcount
+		if attendees <= remaining {
+			return status, nil
+		}
+		if waitlist {
+			return domain.RSVPStatusWaitlist, nil
+		}
+		if remaining < 0 {
+			remaining = 0
+		}
+		return "", NewAppError(http.StatusConflict, fmt.Sprintf("event is full: %d seats left", remaining))
+	}
+}
+
+// rsvpSaveError passes capacity errors through and wraps the rest.
+func rsvpSaveError(err error) error {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return fmt.Errorf("failed to save rsvp: %w", err)
+}
+
+// normalizeAnswers validates answers against the event's questions and
+// returns them as JSON (nil when empty). Unknown question IDs are dropped;
+// required questions only apply to guests who are attending.
+func normalizeAnswers(questions []domain.EventQuestion, answers map[string]interface{}, attending bool) (json.RawMessage, error) {
+	normalized := make(map[string]interface{})
+
+	for _, q := range questions {
+		key := q.ID.String()
+		value, answered := normalizeAnswer(q, answers[key])
+		if value == nil && answered {
+			return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("invalid answer for %q", q.Label))
+		}
+		if value == nil {
+			if q.IsRequired && attending {
+				return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("%q is required", q.Label))
+			}
+			continue
+		}
+		normalized[key] = value
+	}
+
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	raw, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode answers: %w", err)
+	}
+	return raw, nil
+}
+
+// normalizeAnswer returns the cleaned value of one answer. A nil value with
+// answered=true means the answer was given but is not valid.
+func normalizeAnswer(q domain.EventQuestion, raw interface{}) (value interface{}, answered bool) {
+	if raw == nil {
+		return nil, false
+	}
+
+	switch q.Type {
+	case domain.QuestionTypeText:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, true
+		}
+		str = strings.TrimSpace(str)
+		if str == "" {
+			return nil, false
+		}
+		if len(str) > 1000 {
+			return nil, true
+		}
+		return str, true
+	case domain.QuestionTypeSingleChoice:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, true
+		}
+		if str == "" {
+			return nil, false
+		}
+		if !containsString(q.Options, str) {
+			return nil, true
+		}
+		return str, true
+	case domain.QuestionTypeMultiChoice:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return nil, true
+		}
+		var choices []string
+		for _, item := range items {
+			str, ok := item.(string)
+			if !ok || !containsString(q.Options, str) {
+				return nil, true
+			}
+			if !containsString(choices, str) {
+				choices = append(choices, str)
+			}
+		}
+		if len(choices) == 0 {
+			return nil, false
+		}
+		return choices, true
+	case domain.QuestionTypeNumber:
+		n, ok := raw.(float64)
+		if !ok {
+			return nil, true
+		}
+		return n, true
+	}
+	return nil, true
+}
+
+func decodeAnswers(raw json.RawMessage) map[string]interface{} {
+	answers := make(map[string]interface{})
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &answers)
+	}
+	return answers
+}
+
+// aggregateAnswers summarises attending guests' answers per question.
+func aggregateAnswers(questions []domain.EventQuestion, guests []domain.Guest) []domain.QuestionStats {
+	stats := make([]domain.QuestionStats, len(questions))
+	for i, q := range questions {
+		stats[i] = domain.QuestionStats{QuestionID: q.ID, Label: q.Label, Type: q.Type}
+		switch q.Type {
+		case domain.QuestionTypeSingleChoice, domain.QuestionTypeMultiChoice:
+			stats[i].Counts = make(map[string]int, len(q.Options))
+			for _, opt := range q.Options {
+				stats[i].Counts[opt] = 0
+			}
+		case domain.QuestionTypeNumber:
+			sum := 0.0
+			stats[i].Sum = &sum
+		}
+	}
+
+	for _, g := range guests {
+		if g.RSVPStatus != domain.RSVPStatusYes || len(g.Answers) == 0 {
+			continue
+		}
+		answers := decodeAnswers(g.Answers)
+		for i, q := range questions {
+			value, ok := answers[q.ID.String()]
+			if !ok {
+				continue
+			}
+			stats[i].Responses++
+			switch v := value.(type) {
+			case string:
+				if stats[i].Counts != nil {
+					stats[i].Counts[v]++
+				}
+			case []interface{}:
+				for _, item := range v {
+					if str, ok := item.(string); ok && stats[i].Counts != nil {
+						stats[i].Counts[str]++
+					}
+				}
+			case float64:
+				if stats[i].Sum != nil {
+					*stats[i].Sum += v
+				}
+			}
+		}
+	}
+	return stats
+}
+
+// formatAnswer renders a stored answer for exports.
+func formatAnswer(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// resolveSessions returns the session IDs an attending guest will join.
+// Explicit choices must belong to the event; otherwise the guest keeps
+// their current choice, or joins every session the first time.
+func resolveSessions(sessions []domain.EventSession, status domain.RSVPStatus, requested, current []string) ([]string, error) {
+	if len(sessions) == 0 || (status != domain.RSVPStatusYes && status != domain.RSVPStatusWaitlist) {
+		return []string{}, nil
+	}
+
+	valid := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		valid = append(valid, session.ID.String())
+	}
+
+	var chosen []string
+	switch {
+	case requested != nil:
+		for _, id := range requested {
+			if !containsString(valid, id) {
+				return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("unknown session %s", id))
+			}
+			if !containsString(chosen, id) {
+				chosen = append(chosen, id)
+			}
+		}
+		if len(chosen) == 0 {
+			return nil, NewAppError(http.StatusBadRequest, "select at least one session to attend")
+		}
+	case len(current) > 0:
+		for _, id := range current {
+			if containsString(valid, id) {
+				chosen = append(chosen, id)
+			}
+		}
+	}
+	if len(chosen) == 0 {
+		chosen = valid
+	}
+	return chosen, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveAttendees returns the headcount for an answer: only "yes" (or a
+// waitlisted yes) brings people, defaulting to one and capped by the
+// invite's party size.
+func resolveAttendees(status domain.RSVPStatus, requested *int, partySize int) (int, error) {
+	if status != domain.RSVPStatusYes && status != domain.RSVPStatusWaitlist {
+		return 0, nil
+	}
+	attendees := 1
+	if requested != nil {
+		attendees = *requested
+	}
+	if attendees > partySize {
+		return 0, NewAppError(http.StatusBadRequest, fmt.Sprintf("attendees exceeds the party size of %d for this invitation", partySize))
+	}
+	return attendees, nil
+}
+
+func (s *rsvpService) GetGuests(ctx context.Context, userID, eventID uuid.UUID) ([]domain.Guest, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionViewGuests); err != nil {
+		return nil, err
 	}
 
 	guests, err := s.guestRepo.FindByEventID(ctx, eventID)
@@ -65,3 +568,412 @@ func (s *rsvpService) GetGuests(ctx context.Context, userID, eventID uuid.UUID)
 	}
 	return guests, nil
 }
+
+func (s *rsvpService) CreateGuest(ctx context.Context, userID, eventID uuid.UUID, req *domain.CreateGuestRequest) (*domain.Guest, error) {
+	event, err := s.authz.Authorize(ctx, userID, eventID, ActionManageGuests)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.generateUniqueGuestCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	partySize := event.MaxPartySize
+	if req.PartySize != nil {
+		partySize = *req.PartySize
+	}
+
+	guest := &domain.Guest{
+		ID:            uuid.New(),
+		EventID:       eventID,
+		Name:          req.Name,
+		Phone:         req.Phone,
+		RSVPStatus:    domain.RSVPStatusPending,
+		GuestCode:     &code,
+		GuestGroup:    cleanGroup(req.GuestGroup),
+		PartySize:     partySize,
+		SessionIDs:    pq.StringArray{},
+		MessageStatus: domain.MessageStatusPending,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := s.guestRepo.Create(ctx, guest); err != nil {
+		return nil, fmt.Errorf("failed to create guest: %w", err)
+	}
+	return guest, nil
+}
+
+func (s *rsvpService) UpdateGuest(ctx context.Context, userID, eventID, guestID uuid.UUID, req *domain.UpdateGuestRequest) (*domain.Guest, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionManageGuests); err != nil {
+		return nil, err
+	}
+
+	guest, err := s.guestRepo.FindByID(ctx, guestID)
+	if err != nil || guest.EventID != eventID {
+		return nil, NewAppError(http.StatusNotFound, "guest not found")
+	}
+
+	if req.Name != nil {
+		guest.Name = *req.Name
+	}
+	if req.Phone != nil {
+		guest.Phone = req.Phone
+	}
+	if req.PartySize != nil {
+		// The guest's RSVP already counts Attendees seats
+		if *req.PartySize < guest.Attendees {
+			return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("party size cannot be less than the %d attendees already confirmed", guest.Attendees))
+		}
+		guest.PartySize = *req.PartySize
+	}
+	if req.GuestGroup != nil {
+		guest.GuestGroup = cleanGroup(req.GuestGroup)
+	}
+
+	if err := s.guestRepo.Update(ctx, guest); err != nil {
+		return nil, fmt.Errorf("failed to update guest: %w", err)
+	}
+	return guest, nil
+}
+
+// cleanGroup trims a guest group name; blank names mean no group.
+func cleanGroup(group *string) *string {
+	if group == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*group)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func (s *rsvpService) DeleteGuest(ctx context.Context, userID, eventID, guestID uuid.UUID) error {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionManageGuests); err != nil {
+		return err
+	}
+
+	guest, err := s.guestRepo.FindByID(ctx, guestID)
+	if err != nil || guest.EventID != eventID {
+		return NewAppError(http.StatusNotFound, "guest not found")
+	}
+	return s.guestRepo.Delete(ctx, guestID)
+}
+
+// ExportGuests returns the guest list as rows ready to be written as
+// CSV, XLSX or a printable PDF.
+func (s *rsvpService) ExportGuests(ctx context.Context, userID, eventID uuid.UUID) (*domain.GuestExport, error) {
+	event, err := s.authz.Authorize(ctx, userID, eventID, ActionViewGuests)
+	if err != nil {
+		return nil, err
+	}
+
+	guests, err := s.guestRepo.FindByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guests: %w", err)
+	}
+	questions, err := s.eventRepo.FindQuestionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+	sessions, err := s.eventRepo.FindSessionsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	sessionNames := make(map[string]string, len(sessions))
+	for _, session := range sessions {
+		sessionNames[session.ID.String()] = session.Name
+	}
+
+	export := &domain.GuestExport{
+		Event:  event,
+		Header: []string{"Name", "Phone", "Group", "RSVP Status", "Party Size", "Attendees", "Message", "Guest Code", "Created At", "Checked In At"},
+	}
+	if len(sessions) > 0 {
+		export.Header = append(export.Header, "Sessions")
+	}
+	for _, q := range questions {
+		export.Header = append(export.Header, q.Label)
+	}
+
+	for _, g := range guests {
+		row := []string{
+			g.Name,
+			derefString(g.Phone),
+			derefString(g.GuestGroup),
+			string(g.RSVPStatus),
+			strconv.Itoa(g.PartySize),
+			strconv.Itoa(g.Attendees),
+			derefString(g.Message),
+			derefString(g.GuestCode),
+			g.CreatedAt.Format("2006-01-02 15:04"),
+			"",
+		}
+		if g.CheckedInAt != nil {
+			row[len(row)-1] = g.CheckedInAt.Format("2006-01-02 15:04")
+		}
+		if len(sessions) > 0 {
+			var names []string
+			for _, id := range g.SessionIDs {
+				if name, ok := sessionNames[id]; ok {
+					names = append(names, name)
+				}
+			}
+			row = append(row, strings.Join(names, ", "))
+		}
+		answers := decodeAnswers(g.Answers)
+		for _, q := range questions {
+			row = append(row, formatAnswer(answers[q.ID.String()]))
+		}
+		export.Rows = append(export.Rows, row)
+	}
+
+	// Answer totals of attending guests, e.g. for the caterer
+	for _, qs := range aggregateAnswers(questions, guests) {
+		switch {
+		case qs.Counts != nil:
+			for _, opt := range sortedKeys(qs.Counts) {
+				export.Summary = append(export.Summary, []string{qs.Label, opt, strconv.Itoa(qs.Counts[opt])})
+			}
+		case qs.Sum != nil:
+			export.Summary = append(export.Summary, []string{qs.Label, "Total", formatAnswer(*qs.Sum)})
+		default:
+			export.Summary = append(export.Summary, []string{qs.Label, "Responses", strconv.Itoa(qs.Responses)})
+		}
+	}
+	return export, nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ImportGuests pre-registers a guest list from a CSV/XLSX file. Rows are
+// validated first; nothing is inserted unless every row is valid.
+func (s *rsvpService) ImportGuests(ctx context.Context, userID, eventID uuid.UUID, filename string, file io.Reader) (*domain.ImportGuestsResult, error) {
+	event, err := s.authz.Authorize(ctx, userID, eventID, ActionManageGuests)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := utils.ReadSpreadsheet(filename, file)
+	if err != nil {
+		return nil, NewAppError(http.StatusBadRequest, err.Error())
+	}
+	if len(rows) < 2 {
+		return nil, NewAppError(http.StatusBadRequest, "file has no guest rows")
+	}
+	if len(rows)-1 > maxImportRows {
+		return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("file exceeds %d guest rows", maxImportRows))
+	}
+
+	columns := importColumns(rows[0])
+	if _, ok := columns["name"]; !ok {
+		return nil, NewAppError(http.StatusBadRequest, "header row must contain a name column")
+	}
+
+	existing, err := s.guestRepo.FindByEventID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guests: %w", err)
+	}
+	seenPhones := make(map[string]bool, len(existing))
+	for _, g := range existing {
+		if g.Phone != nil && *g.Phone != "" {
+			seenPhones[utils.NormalizePhone(*g.Phone)] = true
+		}
+	}
+
+	result := &domain.ImportGuestsResult{Errors: []domain.ImportGuestRowError{}}
+	var guests []domain.Guest
+	now := time.Now()
+
+	for i, row := range rows[1:] {
+		rowNum := i + 2
+		cell := func(col string) string {
+			idx, ok := columns[col]
+			if !ok || idx >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[idx])
+		}
+
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		result.TotalRows++
+
+		req := domain.RSVPRequest{
+			Name:   cell("name"),
+			Status: domain.RSVPStatusPending,
+		}
+		if phone := cell("phone"); phone != "" {
+			req.Phone = &phone
+		}
+		if err := s.validate.Struct(&req); err != nil {
+			result.Errors = append(result.Errors, domain.ImportGuestRowError{Row: rowNum, Message: err.Error()})
+			continue
+		}
+
+		partySize := event.MaxPartySize
+		if raw := cell("party_size"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > 50 {
+				result.Errors = append(result.Errors, domain.ImportGuestRowError{Row: rowNum, Message: "party_size must be a number between 1 and 50"})
+				continue
+			}
+			partySize = n
+		}
+
+		group := cell("group")
+		if len(group) > 50 {
+			result.Errors = append(result.Errors, domain.ImportGuestRowError{Row: rowNum, Message: "group must be at most 50 characters"})
+			continue
+		}
+
+		if req.Phone != nil {
+			key := utils.NormalizePhone(*req.Phone)
+			if key == "" {
+				result.Errors = append(result.Errors, domain.ImportGuestRowError{Row: rowNum, Message: "phone is invalid"})
+				continue
+			}
+			if seenPhones[key] {
+				result.Errors = append(result.Errors, domain.ImportGuestRowError{Row: rowNum, Message: "duplicate phone " + *req.Phone})
+				continue
+			}
+			seenPhones[key] = true
+		}
+
+		code, err := s.generateUniqueGuestCode(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		guests = append(guests, domain.Guest{
+			ID:            uuid.New(),
+			EventID:       eventID,
+			Name:          req.Name,
+			Phone:         req.Phone,
+			RSVPStatus:    domain.RSVPStatusPending,
+			GuestCode:     &code,
+			GuestGroup:    cleanGroup(&group),
+			PartySize:     partySize,
+			SessionIDs:    pq.StringArray{},
+			MessageStatus: domain.MessageStatusPending,
+			CreatedAt:     now,
+		})
+	}
+
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	if err := s.guestRepo.CreateBatch(ctx, guests); err != nil {
+		return nil, fmt.Errorf("failed to import guests: %w", err)
+	}
+	result.Imported = len(guests)
+	return result, nil
+}
+
+// importColumns maps the header row (English or Indonesian labels) to
+// column indexes.
+func importColumns(header []string) map[string]int {
+	aliases := map[string]string{
+		"name":        "name",
+		"nama":        "name",
+		"phone":       "phone",
+		"telepon":     "phone",
+		"no_hp":       "phone",
+		"hp":          "phone",
+		"whatsapp":    "phone",
+		"party_size":  "party_size",
+		"jumlah":      "party_size",
+		"jumlah_tamu": "party_size",
+		"group":       "group",
+		"guest_group": "group",
+		"grup":        "group",
+		"kelompok":    "group",
+	}
+
+	columns := make(map[string]int)
+	for i, h := range header {
+		h = strings.TrimPrefix(h, "\ufeff")
+		key := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(h)), " ", "_")
+		if col, ok := aliases[key]; ok {
+			if _, dup := columns[col]; !dup {
+				columns[col] = i
+			}
+		}
+	}
+	return columns
+}
+
+// PromoteGuest confirms a waitlisted guest. Owners may knowingly go over
+// capacity, so the limit is not re-checked here.
+func (s *rsvpService) PromoteGuest(ctx context.Context, userID, eventID, guestID uuid.UUID) (*domain.Guest, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionManageGuests); err != nil {
+		return nil, err
+	}
+
+	guest, err := s.guestRepo.FindByID(ctx, guestID)
+	if err != nil || guest.EventID != eventID {
+		return nil, NewAppError(http.StatusNotFound, "guest not found")
+	}
+	if guest.RSVPStatus != domain.RSVPStatusWaitlist {
+		return nil, NewAppError(http.StatusBadRequest, "guest is not on the waitlist")
+	}
+
+	promoted := *guest
+	promoted.RSVPStatus = domain.RSVPStatusYes
+	w := &domain.RSVPWrite{Guest: &promoted, Change: newChange(guest, domain.RSVPChangeSourceOwner)}
+	if err := s.guestRepo.SaveRSVP(ctx, w); err != nil {
+		return nil, fmt.Errorf("failed to promote guest: %w", err)
+	}
+	return &promoted, nil
+}
+
+func (s *rsvpService) GetGuestHistory(ctx context.Context, userID, eventID, guestID uuid.UUID) ([]domain.GuestRSVPChange, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionViewGuests); err != nil {
+		return nil, err
+	}
+
+	guest, err := s.guestRepo.FindByID(ctx, guestID)
+	if err != nil || guest.EventID != eventID {
+		return nil, NewAppError(http.StatusNotFound, "guest not found")
+	}
+
+	changes, err := s.guestRepo.FindChangesByGuestID(ctx, guestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rsvp history: %w", err)
+	}
+	return changes, nil
+}
+
+func (s *rsvpService) generateUniqueGuestCode(ctx context.Context) (string, error) {
+	for {
+		code, err := utils.GenerateGuestCode()
+		if err != nil {
+			return "", err
+		}
+		exists, err := s.guestRepo.GuestCodeExists(ctx, code)
+		if err != nil {
+			return "", fmt.Errorf("failed to check guest code: %w", err)
+		}
+		if !exists {
+			return code, nil
+		}
+	}
+}
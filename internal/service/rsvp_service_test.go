@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestCapacityCheck(t *testing.T) {
+	confirmed := &domain.Guest{RSVPStatus: domain.RSVPStatusYes, Attendees: 2}
+	waitlisted := &domain.Guest{RSVPStatus: domain.RSVPStatusWaitlist, Attendees: 2}
+	declined := &domain.Guest{RSVPStatus: domain.RSVPStatusNo}
+
+	tests := []struct {
+		name      string
+		max       *int
+		waitlist  bool
+		from      *domain.Guest
+		status    domain.RSVPStatus
+		attendees int
+		headcount int
+		// skip means no check runs, so the answer is saved as given
+		skip       bool
+		wantStatus domain.RSVPStatus
+		wantErr    bool
+	}{
+		{name: "no limit", max: nil, status: domain.RSVPStatusYes, attendees: 5, skip: true},
+		{name: "not attending", max: intPtr(10), status: domain.RSVPStatusNo, skip: true},
+		{name: "pending", max: intPtr(10), status: domain.RSVPStatusPending, skip: true},
+
+		{name: "new guest fits", max: intPtr(10), status: domain.RSVPStatusYes, attendees: 2, headcount: 8, wantStatus: domain.RSVPStatusYes},
+		{name: "new guest over capacity", max: intPtr(10), status: domain.RSVPStatusYes, attendees: 3, headcount: 8, wantErr: true},
+		{name: "new guest waitlisted", max: intPtr(10), waitlist: true, status: domain.RSVPStatusYes, attendees: 3, headcount: 8, wantStatus: domain.RSVPStatusWaitlist},
+		{name: "event already over capacity", max: intPtr(10), status: domain.RSVPStatusYes, attendees: 1, headcount: 12, wantErr: true},
+
+		{name: "confirmed guest keeps seats", max: intPtr(10), from: confirmed, status: domain.RSVPStatusYes, attendees: 2, skip: true},
+		{name: "confirmed guest brings fewer", max: intPtr(10), from: confirmed, status: domain.RSVPStatusYes, attendees: 1, skip: true},
+		{name: "confirmed guest brings more and fits", max: intPtr(10), from: confirmed, status: domain.RSVPStatusYes, attendees: 3, headcount: 7, wantStatus: domain.RSVPStatusYes},
+		{name: "confirmed guest brings more over capacity", max: intPtr(10), from: confirmed, status: domain.RSVPStatusYes, attendees: 3, headcount: 8, wantErr: true},
+		{name: "waitlisted guest answers yes again", max: intPtr(10), waitlist: true, from: waitlisted, status: domain.RSVPStatusYes, attendees: 2, headcount: 10, wantStatus: domain.RSVPStatusWaitlist},
+		{name: "declined guest changes to yes", max: intPtr(10), from: declined, status: domain.RSVPStatusYes, attendees: 1, headcount: 9, wantStatus: domain.RSVPStatusYes},
+		{name: "declined guest changes to yes when full", max: intPtr(10), from: declined, status: domain.RSVPStatusYes, attendees: 1, headcount: 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &domain.Event{MaxAttendees: tt.max, WaitlistEnabled: tt.waitlist}
+			decide := capacityCheck(event, tt.from, tt.status, tt.attendees)
+			if tt.skip {
+				if decide != nil {
+					t.Fatal("capacityCheck() returned a check, want none")
+				}
+				return
+			}
+			if decide == nil {
+				t.Fatal("capacityCheck() returned no check")
+			}
+
+			status, err := decide(tt.headcount)
+			if tt.wantErr {
+				var appErr *AppError
+				if !errors.As(err, &appErr) || appErr.Code != http.StatusConflict {
+					t.Fatalf("decide(%d) error = %v, want 409", tt.headcount, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decide(%d) error = %v", tt.headcount, err)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("decide(%d) = %q, want %q", tt.headcount, status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestResolveAttendees(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    domain.RSVPStatus
+		requested *int
+		partySize int
+		want      int
+		wantErr   bool
+	}{
+		{name: "yes defaults to one", status: domain.RSVPStatusYes, partySize: 4, want: 1},
+		{name: "yes with party", status: domain.RSVPStatusYes, requested: intPtr(3), partySize: 4, want: 3},
+		{name: "yes at party size", status: domain.RSVPStatusYes, requested: intPtr(4), partySize: 4, want: 4},
+		{name: "yes over party size", status: domain.RSVPStatusYes, requested: intPtr(5), partySize: 4, wantErr: true},
+		{name: "waitlist counts attendees", status: domain.RSVPStatusWaitlist, requested: intPtr(2), partySize: 4, want: 2},
+		{name: "waitlist over party size", status: domain.RSVPStatusWaitlist, requested: intPtr(5), partySize: 4, wantErr: true},
+		{name: "no brings nobody", status: domain.RSVPStatusNo, requested: intPtr(3), partySize: 4, want: 0},
+		{name: "pending brings nobody", status: domain.RSVPStatusPending, requested: intPtr(9), partySize: 4, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveAttendees(tt.status, tt.requested, tt.partySize)
+			if tt.wantErr {
+				var appErr *AppError
+				if !errors.As(err, &appErr) || appErr.Code != http.StatusBadRequest {
+					t.Fatalf("resolveAttendees() error = %v, want 400", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveAttendees() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveAttendees() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeRSVPGuestRepo stands in for the guest table of one event. SaveRSVP
+// runs the capacity check against a fixed headcount, as the real one does
+// under the event lock.
+type fakeRSVPGuestRepo struct {
+	domain.GuestRepository
+	guest     *domain.Guest
+	headcount int
+	saved     *domain.RSVPWrite
+}
+
+func (r *fakeRSVPGuestRepo) FindByGuestCode(ctx context.Context, code string) (*domain.Guest, error) {
+	if r.guest == nil || r.guest.GuestCode == nil || *r.guest.GuestCode != code {
+		return nil, nil
+	}
+	g := *r.guest
+	return &g, nil
+}
+
+func (r *fakeRSVPGuestRepo) GuestCodeExists(ctx context.Context, code string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeRSVPGuestRepo) SaveRSVP(ctx context.Context, w *domain.RSVPWrite) error {
+	if w.Decide != nil {
+		status, err := w.Decide(r.headcount)
+		if err != nil {
+			return err
+		}
+		w.Guest.RSVPStatus = status
+	}
+	r.saved = w
+	g := *w.Guest
+	r.guest = &g
+	return nil
+}
+
+type fakeRSVPEventRepo struct {
+	domain.EventRepository
+	event *domain.Event
+}
+
+func (r *fakeRSVPEventRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	return r.event, nil
+}
+
+func (r *fakeRSVPEventRepo) FindQuestionsByEventID(ctx context.Context, eventID uuid.UUID) ([]domain.EventQuestion, error) {
+	return nil, nil
+}
+
+func (r *fakeRSVPEventRepo) FindSessionsByEventID(ctx context.Context, eventID uuid.UUID) ([]domain.EventSession, error) {
+	return nil, nil
+}
+
+func TestUpdateByCodeOnFullEvent(t *testing.T) {
+	message := "Selamat menempuh hidup baru"
+
+	tests := []struct {
+		name       string
+		status     domain.RSVPStatus
+		attendees  int
+		waitlist   bool
+		req        domain.UpdateRSVPRequest
+		wantStatus domain.RSVPStatus
+		wantErr    bool
+	}{
+		{
+			name:       "confirmed guest edits only the message",
+			status:     domain.RSVPStatusYes,
+			attendees:  2,
+			req:        domain.UpdateRSVPRequest{Message: &message},
+			wantStatus: domain.RSVPStatusYes,
+		},
+		{
+			name:       "confirmed guest edits the message with waitlist on",
+			status:     domain.RSVPStatusYes,
+			attendees:  2,
+			waitlist:   true,
+			req:        domain.UpdateRSVPRequest{Message: &message},
+			wantStatus: domain.RSVPStatusYes,
+		},
+		{
+			name:       "confirmed guest brings fewer",
+			status:     domain.RSVPStatusYes,
+			attendees:  2,
+			req:        domain.UpdateRSVPRequest{Attendees: intPtr(1)},
+			wantStatus: domain.RSVPStatusYes,
+		},
+		{
+			name:      "confirmed guest brings more",
+			status:    domain.RSVPStatusYes,
+			attendees: 2,
+			req:       domain.UpdateRSVPRequest{Attendees: intPtr(3)},
+			wantErr:   true,
+		},
+		{
+			name:       "waitlisted guest stays waitlisted",
+			status:     domain.RSVPStatusWaitlist,
+			attendees:  2,
+			waitlist:   true,
+			req:        domain.UpdateRSVPRequest{Message: &message},
+			wantStatus: domain.RSVPStatusWaitlist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := "AB12CD34"
+			guest := &domain.Guest{
+				ID:         uuid.New(),
+				EventID:    uuid.New(),
+				Name:       "Rina",
+				RSVPStatus: tt.status,
+				GuestCode:  &code,
+				PartySize:  4,
+				Attendees:  tt.attendees,
+			}
+			// The owner lowered the limit below what is already confirmed
+			event := &domain.Event{
+				ID:              guest.EventID,
+				IsPublished:     true,
+				MaxAttendees:    intPtr(5),
+				WaitlistEnabled: tt.waitlist,
+			}
+			guests := &fakeRSVPGuestRepo{guest: guest, headcount: 6}
+			svc := NewRSVPService(guests, &fakeRSVPEventRepo{event: event}, nil)
+
+			got, err := svc.UpdateByCode(context.Background(), code, &tt.req)
+			if tt.wantErr {
+				var appErr *AppError
+				if !errors.As(err, &appErr) || appErr.Code != http.StatusConflict {
+					t.Fatalf("UpdateByCode() error = %v, want 409", err)
+				}
+				if guests.saved != nil {
+					t.Error("UpdateByCode() saved a rejected answer")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateByCode() error = %v", err)
+			}
+			if got.RSVPStatus != tt.wantStatus || guests.guest.RSVPStatus != tt.wantStatus {
+				t.Errorf("status = %q (saved %q), want %q", got.RSVPStatus, guests.guest.RSVPStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSubmitCapacity(t *testing.T) {
+	tests := []struct {
+		name       string
+		max        *int
+		waitlist   bool
+		headcount  int
+		status     domain.RSVPStatus
+		attendees  int
+		wantStatus domain.RSVPStatus
+		wantErr    bool
+	}{
+		{name: "seats left", max: intPtr(10), headcount: 7, status: domain.RSVPStatusYes, attendees: 3, wantStatus: domain.RSVPStatusYes},
+		{name: "full", max: intPtr(10), headcount: 8, status: domain.RSVPStatusYes, attendees: 3, wantErr: true},
+		{name: "full with waitlist", max: intPtr(10), waitlist: true, headcount: 8, status: domain.RSVPStatusYes, attendees: 3, wantStatus: domain.RSVPStatusWaitlist},
+		{name: "declining on a full event", max: intPtr(10), headcount: 10, status: domain.RSVPStatusNo, wantStatus: domain.RSVPStatusNo},
+		{name: "no limit", headcount: 500, status: domain.RSVPStatusYes, attendees: 3, wantStatus: domain.RSVPStatusYes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &domain.Event{
+				ID:              uuid.New(),
+				IsPublished:     true,
+				MaxPartySize:    5,
+				MaxAttendees:    tt.max,
+				WaitlistEnabled: tt.waitlist,
+			}
+			guests := &fakeRSVPGuestRepo{headcount: tt.headcount}
+			svc := NewRSVPService(guests, &fakeRSVPEventRepo{event: event}, nil)
+
+			req := &domain.RSVPRequest{Name: "Dimas", Status: tt.status}
+			if tt.attendees > 0 {
+				req.Attendees = intPtr(tt.attendees)
+			}
+			got, err := svc.Submit(context.Background(), event.ID, req)
+			if tt.wantErr {
+				var appErr *AppError
+				if !errors.As(err, &appErr) || appErr.Code != http.StatusConflict {
+					t.Fatalf("Submit() error = %v, want 409", err)
+				}
+				if guests.saved != nil {
+					t.Error("Submit() saved a rejected answer")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+			if got.RSVPStatus != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got.RSVPStatus, tt.wantStatus)
+			}
+			if !guests.saved.Create {
+				t.Error("Submit() did not create the guest")
+			}
+		})
+	}
+}
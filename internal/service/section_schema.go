@@ -0,0 +1,316 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+// fieldKind is the value type of a section content field.
+type fieldKind string
+
+const (
+	kindString   fieldKind = "string"   // single line, up to 300 characters
+	kindText     fieldKind = "text"     // multi-line, up to 5000 characters
+	kindURL      fieldKind = "url"      // absolute http(s) URL or /uploads path
+	kindDateTime fieldKind = "datetime" // RFC 3339 timestamp
+	kindObject   fieldKind = "object"
+	kindList     fieldKind = "list" // list of objects
+)
+
+// sectionField describes one field of section content. Object and list
+// fields describe their members (or list items) in Fields.
+type sectionField struct {
+	Name        string
+	Kind        fieldKind
+	Required    bool
+	MaxLength   int
+	MaxItems    int
+	Description string
+	Fields      []sectionField
+}
+
+var personFields = []sectionField{
+	{Name: "name", Kind: kindString, Required: true, MaxLength: 100, Description: "Short name shown in headings"},
+	{Name: "full_name", Kind: kindString, MaxLength: 200},
+	{Name: "parents", Kind: kindString, Description: "e.g. \"Putri dari Bapak ... & Ibu ...\""},
+	{Name: "photo_url", Kind: kindURL},
+	{Name: "instagram", Kind: kindString, MaxLength: 100},
+}
+
+// sectionSchemas is the content contract of each known section type.
+// Content of other types is free-form but must still be a JSON object.
+var sectionSchemas = map[string][]sectionField{
+	"hero": {
+		{Name: "title", Kind: kindString, Description: "Defaults to the event title"},
+		{Name: "subtitle", Kind: kindString},
+		{Name: "image_url", Kind: kindURL, Description: "Defaults to the gallery cover photo"},
+	},
+	"couple": {
+		{Name: "title", Kind: kindString},
+		{Name: "bride", Kind: kindObject, Required: true, Fields: personFields},
+		{Name: "groom", Kind: kindObject, Required: true, Fields: personFields},
+	},
+	"countdown": {
+		{Name: "title", Kind: kindString},
+		{Name: "target_date", Kind: kindDateTime, Description: "Defaults to the event date"},
+	},
+	"event": {
+		{Name: "title", Kind: kindString},
+	},
+	"location": {
+		{Name: "title", Kind: kindString},
+		{Name: "address", Kind: kindText, MaxLength: 500, Description: "Defaults to the event address"},
+		{Name: "map_url", Kind: kindURL},
+	},
+	"gallery": {
+		{Name: "title", Kind: kindString},
+	},
+	"rsvp": {
+		{Name: "title", Kind: kindString},
+		{Name: "body", Kind: kindText},
+	},
+	"wishes": {
+		{Name: "title", Kind: kindString},
+	},
+	"gift": {
+		{Name: "title", Kind: kindString},
+		{Name: "body", Kind: kindText},
+		{Name: "accounts", Kind: kindList, MaxItems: 10, Fields: []sectionField{
+			{Name: "bank", Kind: kindString, Required: true, MaxLength: 100, Description: "Bank or e-wallet name"},
+			{Name: "number", Kind: kindString, Required: true, MaxLength: 50},
+			{Name: "name", Kind: kindString, Required: true, MaxLength: 150, Description: "Account holder"},
+		}},
+	},
+	"story": {
+		{Name: "title", Kind: kindString},
+		{Name: "items", Kind: kindList, MaxItems: 20, Fields: []sectionField{
+			{Name: "date", Kind: kindString, MaxLength: 50, Description: "Free text, e.g. \"Juni 2019\""},
+			{Name: "title", Kind: kindString, Required: true},
+			{Name: "body", Kind: kindText},
+			{Name: "image_url", Kind: kindURL},
+		}},
+	},
+	"quote": {
+		{Name: "text", Kind: kindText, Required: true, MaxLength: 1000},
+		{Name: "source", Kind: kindString, MaxLength: 150},
+	},
+}
+
+const (
+	defaultStringLength = 300
+	defaultTextLength   = 5000
+	defaultURLLength    = 2000
+)
+
+// SectionSchemas returns the JSON Schema of every known section type,
+// sorted by type.
+func SectionSchemas() []domain.SectionSchema {
+	types := make([]string, 0, len(sectionSchemas))
+	for t := range sectionSchemas {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	schemas := make([]domain.SectionSchema, 0, len(types))
+	for _, t := range types {
+		schema := objectSchema(sectionSchemas[t])
+		schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+		schema["title"] = t
+		schemas = append(schemas, domain.SectionSchema{Type: t, Schema: schema})
+	}
+	return schemas
+}
+
+// ValidateSectionContent checks content against the schema of sectionType
+// and returns one error per invalid field.
+func ValidateSectionContent(sectionType string, content json.RawMessage) []domain.FieldError {
+	var data map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil || data == nil {
+		return []domain.FieldError{{Field: "content", Message: "must be a JSON object"}}
+	}
+
+	fields, ok := sectionSchemas[sectionType]
+	if !ok {
+		return nil
+	}
+	var errs []domain.FieldError
+	validateObject(fields, data, "", &errs)
+	return errs
+}
+
+// newSectionContentError is the 422 returned for invalid section content.
+func newSectionContentError(errs []domain.FieldError) *AppError {
+	return NewValidationError("invalid section content", errs)
+}
+
+func validateObject(fields []sectionField, data map[string]interface{}, prefix string, errs *[]domain.FieldError) {
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.Name] = true
+		path := prefix + f.Name
+		v, present := data[f.Name]
+		if !present || v == nil || v == "" {
+			if f.Required {
+				*errs = append(*errs, domain.FieldError{Field: path, Message: "is required"})
+			}
+			continue
+		}
+		validateField(f, v, path, errs)
+	}
+
+	// Unknown keys are most likely typos the editor would silently drop
+	unknown := make([]string, 0)
+	for k := range data {
+		if !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	for _, k := range unknown {
+		*errs = append(*errs, domain.FieldError{Field: prefix + k, Message: "is not a known field"})
+	}
+}
+
+func validateField(f sectionField, v interface{}, path string, errs *[]domain.FieldError) {
+	fail := func(msg string) {
+		*errs = append(*errs, domain.FieldError{Field: path, Message: msg})
+	}
+
+	switch f.Kind {
+	case kindString, kindText, kindURL, kindDateTime:
+		s, ok := v.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		if max := f.maxLength(); max > 0 && utf8.RuneCountInString(s) > max {
+			fail(fmt.Sprintf("must be at most %d characters", max))
+			return
+		}
+		switch f.Kind {
+		case kindString:
+			if strings.ContainsAny(s, "\r\n") {
+				fail("must be a single line")
+			}
+		case kindURL:
+			if !isContentURL(s) {
+				fail("must be an http(s) URL")
+			}
+		case kindDateTime:
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				fail("must be an RFC 3339 date-time, e.g. 2025-12-31T19:00:00+07:00")
+			}
+		}
+	case kindObject:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		validateObject(f.Fields, obj, path+".", errs)
+	case kindList:
+		list, ok := v.([]interface{})
+		if !ok {
+			fail("must be a list")
+			return
+		}
+		if f.MaxItems > 0 && len(list) > f.MaxItems {
+			fail(fmt.Sprintf("must have at most %d items", f.MaxItems))
+			return
+		}
+		for i, item := range list {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				*errs = append(*errs, domain.FieldError{Field: itemPath, Message: "must be an object"})
+				continue
+			}
+			validateObject(f.Fields, obj, itemPath+".", errs)
+		}
+	}
+}
+
+func (f sectionField) maxLength() int {
+	if f.MaxLength > 0 {
+		return f.MaxLength
+	}
+	switch f.Kind {
+	case kindString:
+		return defaultStringLength
+	case kindURL:
+		return defaultURLLength
+	case kindText:
+		return defaultTextLength
+	}
+	return 0
+}
+
+// isContentURL accepts absolute http(s) URLs and paths on this host, such
+// as local uploads.
+func isContentURL(s string) bool {
+	if strings.HasPrefix(s, "/") && !strings.HasPrefix(s, "//") {
+		return true
+	}
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func objectSchema(fields []sectionField) map[string]interface{} {
+	properties := make(map[string]interface{}, len(fields))
+	required := make([]string, 0)
+	for _, f := range fields {
+		properties[f.Name] = fieldSchema(f)
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func fieldSchema(f sectionField) map[string]interface{} {
+	var schema map[string]interface{}
+	switch f.Kind {
+	case kindObject:
+		schema = objectSchema(f.Fields)
+	case kindList:
+		schema = map[string]interface{}{"type": "array", "items": objectSchema(f.Fields)}
+		if f.MaxItems > 0 {
+			schema["maxItems"] = f.MaxItems
+		}
+	default:
+		schema = map[string]interface{}{"type": "string"}
+		if max := f.maxLength(); max > 0 {
+			schema["maxLength"] = max
+		}
+		switch f.Kind {
+		case kindURL:
+			schema["format"] = "uri-reference"
+		case kindDateTime:
+			schema["format"] = "date-time"
+		case kindText:
+			schema["x-multiline"] = true
+		}
+	}
+	if f.Description != "" {
+		schema["description"] = f.Description
+	}
+	return schema
+}
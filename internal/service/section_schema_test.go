@@ -0,0 +1,286 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+func TestValidateSectionContent(t *testing.T) {
+	person := `{"name": "Rina"}`
+	accounts := func(n int) string {
+		items := make([]string, n)
+		for i := range items {
+			items[i] = `{"bank": "BCA", "number": "123", "name": "Rina"}`
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	}
+
+	tests := []struct {
+		name        string
+		sectionType string
+		content     string
+		want        []domain.FieldError
+	}{
+		{
+			name:        "valid content",
+			sectionType: "hero",
+			content:     `{"title": "Rina & Dimas", "image_url": "https://cdn.example.com/a.jpg"}`,
+		},
+		{
+			name:        "empty object",
+			sectionType: "hero",
+			content:     `{}`,
+		},
+		{
+			name:        "not an object",
+			sectionType: "hero",
+			content:     `["title"]`,
+			want:        []domain.FieldError{{Field: "content", Message: "must be a JSON object"}},
+		},
+		{
+			name:        "invalid JSON",
+			sectionType: "hero",
+			content:     `{"title":`,
+			want:        []domain.FieldError{{Field: "content", Message: "must be a JSON object"}},
+		},
+		{
+			name:        "unknown type is free-form",
+			sectionType: "custom",
+			content:     `{"anything": [1, 2, 3]}`,
+		},
+		{
+			name:        "unknown type must still be an object",
+			sectionType: "custom",
+			content:     `"text"`,
+			want:        []domain.FieldError{{Field: "content", Message: "must be a JSON object"}},
+		},
+
+		// Required fields
+		{
+			name:        "required field missing",
+			sectionType: "quote",
+			content:     `{"source": "QS. Ar-Rum: 21"}`,
+			want:        []domain.FieldError{{Field: "text", Message: "is required"}},
+		},
+		{
+			name:        "required field blank",
+			sectionType: "quote",
+			content:     `{"text": ""}`,
+			want:        []domain.FieldError{{Field: "text", Message: "is required"}},
+		},
+		{
+			name:        "required field null",
+			sectionType: "quote",
+			content:     `{"text": null}`,
+			want:        []domain.FieldError{{Field: "text", Message: "is required"}},
+		},
+		{
+			name:        "required nested objects missing",
+			sectionType: "couple",
+			content:     `{"title": "Mempelai"}`,
+			want: []domain.FieldError{
+				{Field: "bride", Message: "is required"},
+				{Field: "groom", Message: "is required"},
+			},
+		},
+		{
+			name:        "required field inside nested object",
+			sectionType: "couple",
+			content:     `{"bride": {"full_name": "Rina Putri"}, "groom": ` + person + `}`,
+			want:        []domain.FieldError{{Field: "bride.name", Message: "is required"}},
+		},
+		{
+			name:        "required field inside list item",
+			sectionType: "gift",
+			content:     `{"accounts": [{"bank": "BCA", "number": "123"}]}`,
+			want:        []domain.FieldError{{Field: "accounts[0].name", Message: "is required"}},
+		},
+
+		// Unknown keys
+		{
+			name:        "unknown top-level keys sorted",
+			sectionType: "hero",
+			content:     `{"titel": "x", "subtitel": "y"}`,
+			want: []domain.FieldError{
+				{Field: "subtitel", Message: "is not a known field"},
+				{Field: "titel", Message: "is not a known field"},
+			},
+		},
+		{
+			name:        "unknown nested key",
+			sectionType: "couple",
+			content:     `{"bride": {"name": "Rina", "nickname": "Rin"}, "groom": ` + person + `}`,
+			want:        []domain.FieldError{{Field: "bride.nickname", Message: "is not a known field"}},
+		},
+		{
+			name:        "unknown key in list item",
+			sectionType: "story",
+			content:     `{"items": [{"title": "Bertemu", "place": "Bandung"}]}`,
+			want:        []domain.FieldError{{Field: "items[0].place", Message: "is not a known field"}},
+		},
+
+		// Types and lengths
+		{
+			name:        "wrong scalar type",
+			sectionType: "hero",
+			content:     `{"title": 42}`,
+			want:        []domain.FieldError{{Field: "title", Message: "must be a string"}},
+		},
+		{
+			name:        "string must be single line",
+			sectionType: "hero",
+			content:     `{"title": "Rina\nDimas"}`,
+			want:        []domain.FieldError{{Field: "title", Message: "must be a single line"}},
+		},
+		{
+			name:        "text may span lines",
+			sectionType: "rsvp",
+			content:     `{"body": "Mohon konfirmasi\nkehadiran"}`,
+		},
+		{
+			name:        "default string length",
+			sectionType: "hero",
+			content:     fmt.Sprintf(`{"title": %q}`, strings.Repeat("a", defaultStringLength+1)),
+			want:        []domain.FieldError{{Field: "title", Message: "must be at most 300 characters"}},
+		},
+		{
+			name:        "length counts characters not bytes",
+			sectionType: "couple",
+			content:     fmt.Sprintf(`{"bride": {"name": %q}, "groom": %s}`, strings.Repeat("é", 100), person),
+		},
+		{
+			name:        "field max length",
+			sectionType: "couple",
+			content:     fmt.Sprintf(`{"bride": {"name": %q}, "groom": %s}`, strings.Repeat("a", 101), person),
+			want:        []domain.FieldError{{Field: "bride.name", Message: "must be at most 100 characters"}},
+		},
+		{
+			name:        "object field not an object",
+			sectionType: "couple",
+			content:     `{"bride": "Rina", "groom": ` + person + `}`,
+			want:        []domain.FieldError{{Field: "bride", Message: "must be an object"}},
+		},
+
+		// List limits
+		{
+			name:        "list at max items",
+			sectionType: "gift",
+			content:     `{"accounts": ` + accounts(10) + `}`,
+		},
+		{
+			name:        "list over max items",
+			sectionType: "gift",
+			content:     `{"accounts": ` + accounts(11) + `}`,
+			want:        []domain.FieldError{{Field: "accounts", Message: "must have at most 10 items"}},
+		},
+		{
+			name:        "list field not a list",
+			sectionType: "gift",
+			content:     `{"accounts": {"bank": "BCA"}}`,
+			want:        []domain.FieldError{{Field: "accounts", Message: "must be a list"}},
+		},
+		{
+			name:        "list item not an object",
+			sectionType: "story",
+			content:     `{"items": ["Bertemu", {"title": "Menikah"}]}`,
+			want:        []domain.FieldError{{Field: "items[0]", Message: "must be an object"}},
+		},
+
+		// URL formats
+		{
+			name:        "https URL",
+			sectionType: "location",
+			content:     `{"map_url": "https://maps.google.com/?q=Bandung"}`,
+		},
+		{
+			name:        "local upload path",
+			sectionType: "hero",
+			content:     `{"image_url": "/uploads/events/abc/cover.jpg"}`,
+		},
+		{
+			name:        "protocol-relative URL",
+			sectionType: "hero",
+			content:     `{"image_url": "//cdn.example.com/a.jpg"}`,
+			want:        []domain.FieldError{{Field: "image_url", Message: "must be an http(s) URL"}},
+		},
+		{
+			name:        "javascript URL",
+			sectionType: "location",
+			content:     `{"map_url": "javascript:alert(1)"}`,
+			want:        []domain.FieldError{{Field: "map_url", Message: "must be an http(s) URL"}},
+		},
+		{
+			name:        "URL without host",
+			sectionType: "location",
+			content:     `{"map_url": "https://"}`,
+			want:        []domain.FieldError{{Field: "map_url", Message: "must be an http(s) URL"}},
+		},
+		{
+			name:        "relative URL",
+			sectionType: "story",
+			content:     `{"items": [{"title": "Bertemu", "image_url": "photo.jpg"}]}`,
+			want:        []domain.FieldError{{Field: "items[0].image_url", Message: "must be an http(s) URL"}},
+		},
+
+		// Date formats
+		{
+			name:        "RFC 3339 date-time",
+			sectionType: "countdown",
+			content:     `{"target_date": "2025-12-31T19:00:00+07:00"}`,
+		},
+		{
+			name:        "UTC date-time",
+			sectionType: "countdown",
+			content:     `{"target_date": "2025-12-31T12:00:00Z"}`,
+		},
+		{
+			name:        "date without time",
+			sectionType: "countdown",
+			content:     `{"target_date": "2025-12-31"}`,
+			want: []domain.FieldError{
+				{Field: "target_date", Message: "must be an RFC 3339 date-time, e.g. 2025-12-31T19:00:00+07:00"},
+			},
+		},
+		{
+			name:        "date-time without zone",
+			sectionType: "countdown",
+			content:     `{"target_date": "2025-12-31T19:00:00"}`,
+			want: []domain.FieldError{
+				{Field: "target_date", Message: "must be an RFC 3339 date-time, e.g. 2025-12-31T19:00:00+07:00"},
+			},
+		},
+		{
+			name:        "story date is free text",
+			sectionType: "story",
+			content:     `{"items": [{"date": "Juni 2019", "title": "Bertemu"}]}`,
+		},
+
+		{
+			name:        "errors from several fields",
+			sectionType: "gift",
+			content:     `{"title": 1, "accounts": [{"bank": "BCA", "number": "123", "name": "Rina", "branch": "x"}], "note": "y"}`,
+			want: []domain.FieldError{
+				{Field: "title", Message: "must be a string"},
+				{Field: "accounts[0].branch", Message: "is not a known field"},
+				{Field: "note", Message: "is not a known field"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateSectionContent(tt.sectionType, json.RawMessage(tt.content))
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateSectionContent(%q) = %+v, want %+v", tt.sectionType, got, tt.want)
+			}
+		})
+	}
+}
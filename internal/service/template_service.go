@@ -1,38 +1,69 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/config"
 	"github.com/galihaleanda/event-invitation/internal/domain"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/imaging"
+	"github.com/galihaleanda/event-invitation/internal/infrastructure/storage"
+)
+
+const (
+	thumbnailWidth   = 800
+	thumbnailQuality = 85
 )
 
 type TemplateService interface {
-	GetAll(ctx context.Context, category string) ([]domain.Template, error)
-	GetByID(ctx context.Context, id uuid.UUID) (*domain.Template, error)
+	// GetAll and GetByID only see inactive templates when includeInactive
+	// is set, which is reserved for admins
+	GetAll(ctx context.Context, category string, includeInactive bool) ([]domain.Template, error)
+	GetByID(ctx context.Context, id uuid.UUID, includeInactive bool) (*domain.Template, error)
+	GetSectionSchemas() []domain.SectionSchema
+
+	// Admin
+	Create(ctx context.Context, req *domain.CreateTemplateRequest) (*domain.Template, error)
+	Update(ctx context.Context, id uuid.UUID, req *domain.UpdateTemplateRequest) (*domain.Template, error)
+	Reorder(ctx context.Context, req *domain.ReorderTemplatesRequest) ([]domain.Template, error)
+	UploadThumbnail(ctx context.Context, id uuid.UUID, file *multipart.FileHeader) (*domain.Template, error)
+	CreateSection(ctx context.Context, templateID uuid.UUID, req *domain.CreateTemplateSectionRequest) (*domain.TemplateSection, error)
+	UpdateSection(ctx context.Context, templateID, sectionID uuid.UUID, req *domain.UpdateTemplateSectionRequest) (*domain.TemplateSection, error)
+	DeleteSection(ctx context.Context, templateID, sectionID uuid.UUID) error
+	ReorderSections(ctx context.Context, templateID uuid.UUID, req *domain.ReorderTemplateSectionsRequest) ([]domain.TemplateSection, error)
 }
 
 type templateService struct {
 	templateRepo domain.TemplateRepository
+	storage      storage.Storage
+	cfg          config.StorageConfig
 }
 
-func NewTemplateService(templateRepo domain.TemplateRepository) TemplateService {
-	return &templateService{templateRepo: templateRepo}
+func NewTemplateService(templateRepo domain.TemplateRepository, store storage.Storage, cfg *config.Config) TemplateService {
+	return &templateService{templateRepo: templateRepo, storage: store, cfg: cfg.Storage}
 }
 
-func (s *templateService) GetAll(ctx context.Context, category string) ([]domain.Template, error) {
-	templates, err := s.templateRepo.FindAll(ctx, category)
+func (s *templateService) GetAll(ctx context.Context, category string, includeInactive bool) ([]domain.Template, error) {
+	templates, err := s.templateRepo.FindAll(ctx, category, includeInactive)
 	if err != nil {
 		return nil, fmt.Errorf("templateService.GetAll: %w", err)
 	}
 	return templates, nil
 }
 
-func (s *templateService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Template, error) {
-	tmpl, err := s.templateRepo.FindByID(ctx, id)
+func (s *templateService) GetByID(ctx context.Context, id uuid.UUID, includeInactive bool) (*domain.Template, error) {
+	tmpl, err := s.findTemplate(ctx, id, includeInactive)
 	if err != nil {
-		return nil, fmt.Errorf("templateService.GetByID: %w", err)
+		return nil, err
 	}
 
 	sections, err := s.templateRepo.FindSectionsByTemplateID(ctx, id)
@@ -43,3 +74,284 @@ func (s *templateService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Te
 
 	return tmpl, nil
 }
+
+// GetSectionSchemas returns the content schema of each section type, the
+// contract section editors are built against.
+func (s *templateService) GetSectionSchemas() []domain.SectionSchema {
+	return SectionSchemas()
+}
+
+func (s *templateService) Create(ctx context.Context, req *domain.CreateTemplateRequest) (*domain.Template, error) {
+	now := time.Now()
+	tmpl := &domain.Template{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Category:  strings.ToLower(strings.TrimSpace(req.Category)),
+		IsActive:  req.IsActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.templateRepo.Create(ctx, tmpl); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (s *templateService) Update(ctx context.Context, id uuid.UUID, req *domain.UpdateTemplateRequest) (*domain.Template, error) {
+	tmpl, err := s.findTemplate(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		tmpl.Name = *req.Name
+	}
+	if req.Category != nil {
+		tmpl.Category = strings.ToLower(strings.TrimSpace(*req.Category))
+	}
+	if req.IsActive != nil {
+		tmpl.IsActive = *req.IsActive
+	}
+	tmpl.UpdatedAt = time.Now()
+
+	if err := s.templateRepo.Update(ctx, tmpl); err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (s *templateService) Reorder(ctx context.Context, req *domain.ReorderTemplatesRequest) ([]domain.Template, error) {
+	templates, err := s.templateRepo.FindAll(ctx, "", true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+	known := make(map[uuid.UUID]bool, len(templates))
+	for _, t := range templates {
+		known[t.ID] = true
+	}
+
+	ids, err := parseOrder(req.TemplateIDs, known, "template")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.Reorder(ctx, ids); err != nil {
+		return nil, fmt.Errorf("failed to reorder templates: %w", err)
+	}
+	return s.GetAll(ctx, "", true)
+}
+
+// UploadThumbnail stores a resized JPEG copy of the image, which also
+// drops its camera metadata, and replaces the previous thumbnail.
+func (s *templateService) UploadThumbnail(ctx context.Context, id uuid.UUID, file *multipart.FileHeader) (*domain.Template, error) {
+	tmpl, err := s.findTemplate(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.MaxImageSize > 0 && file.Size > s.cfg.MaxImageSize {
+		return nil, NewAppError(http.StatusRequestEntityTooLarge, fmt.Sprintf("images may be at most %s", formatSize(s.cfg.MaxImageSize)))
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, NewAppError(http.StatusBadRequest, "failed to read file")
+	}
+	defer src.Close()
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, NewAppError(http.StatusBadRequest, "failed to read file")
+	}
+
+	contentType := storage.SniffContentType(data)
+	if kind, ok := allowedMediaTypes[contentType]; !ok || kind.mediaType != domain.MediaTypeImage {
+		return nil, NewAppError(http.StatusUnsupportedMediaType, "thumbnail must be a JPEG, PNG, GIF or WebP image")
+	}
+	img, _, err := imaging.Decode(data)
+	if err != nil {
+		return nil, NewAppError(http.StatusBadRequest, "thumbnail could not be decoded")
+	}
+	img = imaging.Orient(img, imaging.Orientation(data))
+	if img.Bounds().Dx() > thumbnailWidth {
+		img = imaging.Resize(img, thumbnailWidth)
+	}
+	thumb, err := imaging.EncodeJPEG(img, thumbnailQuality)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+
+	key := fmt.Sprintf("templates/%s/%s.jpg", tmpl.ID, uuid.New())
+	if err := s.storage.Put(ctx, key, bytes.NewReader(thumb), int64(len(thumb)), "image/jpeg"); err != nil {
+		return nil, fmt.Errorf("failed to save thumbnail: %w", err)
+	}
+
+	oldKey := tmpl.ThumbnailKey
+	url := s.storage.URL(key)
+	tmpl.ThumbnailURL = &url
+	tmpl.ThumbnailKey = &key
+	tmpl.UpdatedAt = time.Now()
+	if err := s.templateRepo.Update(ctx, tmpl); err != nil {
+		_ = s.storage.Delete(ctx, key)
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+	if oldKey != nil {
+		if err := s.storage.Delete(ctx, *oldKey); err != nil {
+			log.Printf("failed to delete old thumbnail %s: %v", *oldKey, err)
+		}
+	}
+	return tmpl, nil
+}
+
+func (s *templateService) CreateSection(ctx context.Context, templateID uuid.UUID, req *domain.CreateTemplateSectionRequest) (*domain.TemplateSection, error) {
+	if _, err := s.findTemplate(ctx, templateID, true); err != nil {
+		return nil, err
+	}
+
+	content := req.DefaultContent
+	if len(content) == 0 {
+		content = json.RawMessage(`{}`)
+	}
+	sectionType := strings.TrimSpace(req.Type)
+	if errs := ValidateSectionContent(sectionType, content); len(errs) > 0 {
+		return nil, NewValidationError("invalid default content", errs)
+	}
+
+	section := &domain.TemplateSection{
+		ID:             uuid.New(),
+		TemplateID:     templateID,
+		Name:           req.Name,
+		Type:           sectionType,
+		DefaultContent: content,
+	}
+	if err := s.templateRepo.CreateSection(ctx, section); err != nil {
+		return nil, fmt.Errorf("failed to create section: %w", err)
+	}
+	return section, nil
+}
+
+func (s *templateService) UpdateSection(ctx context.Context, templateID, sectionID uuid.UUID, req *domain.UpdateTemplateSectionRequest) (*domain.TemplateSection, error) {
+	section, err := s.findSection(ctx, templateID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		section.Name = *req.Name
+	}
+	if req.Type != nil && strings.TrimSpace(*req.Type) != section.Type {
+		// Events render their saved content with the section's live type
+		inUse, err := s.templateRepo.SectionInUse(ctx, sectionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check section usage: %w", err)
+		}
+		if inUse {
+			return nil, NewAppError(http.StatusConflict, "section type cannot change while existing events use it")
+		}
+		section.Type = strings.TrimSpace(*req.Type)
+	}
+	if req.DefaultContent != nil {
+		section.DefaultContent = req.DefaultContent
+	}
+	// A new type must still fit the current default content
+	if req.Type != nil || req.DefaultContent != nil {
+		content := section.DefaultContent
+		if len(content) == 0 {
+			content = json.RawMessage(`{}`)
+		}
+		if errs := ValidateSectionContent(section.Type, content); len(errs) > 0 {
+			return nil, NewValidationError("invalid default content", errs)
+		}
+	}
+
+	if err := s.templateRepo.UpdateSection(ctx, section); err != nil {
+		return nil, fmt.Errorf("failed to update section: %w", err)
+	}
+	return section, nil
+}
+
+// DeleteSection removes a section no event was created with yet; sections
+// already copied into events are kept so those events still render.
+func (s *templateService) DeleteSection(ctx context.Context, templateID, sectionID uuid.UUID) error {
+	if _, err := s.findSection(ctx, templateID, sectionID); err != nil {
+		return err
+	}
+
+	inUse, err := s.templateRepo.SectionInUse(ctx, sectionID)
+	if err != nil {
+		return fmt.Errorf("failed to check section usage: %w", err)
+	}
+	if inUse {
+		return NewAppError(http.StatusConflict, "section is used by existing events")
+	}
+
+	if err := s.templateRepo.DeleteSection(ctx, templateID, sectionID); err != nil {
+		return fmt.Errorf("failed to delete section: %w", err)
+	}
+	return nil
+}
+
+func (s *templateService) ReorderSections(ctx context.Context, templateID uuid.UUID, req *domain.ReorderTemplateSectionsRequest) ([]domain.TemplateSection, error) {
+	if _, err := s.findTemplate(ctx, templateID, true); err != nil {
+		return nil, err
+	}
+	sections, err := s.templateRepo.FindSectionsByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+	known := make(map[uuid.UUID]bool, len(sections))
+	for _, sec := range sections {
+		known[sec.ID] = true
+	}
+
+	ids, err := parseOrder(req.SectionIDs, known, "section")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.ReorderSections(ctx, templateID, ids); err != nil {
+		return nil, fmt.Errorf("failed to reorder sections: %w", err)
+	}
+
+	sections, err = s.templateRepo.FindSectionsByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+	return sections, nil
+}
+
+func (s *templateService) findTemplate(ctx context.Context, id uuid.UUID, includeInactive bool) (*domain.Template, error) {
+	tmpl, err := s.templateRepo.FindByID(ctx, id)
+	if err != nil || tmpl == nil || (!tmpl.IsActive && !includeInactive) {
+		return nil, NewAppError(http.StatusNotFound, "template not found")
+	}
+	return tmpl, nil
+}
+
+func (s *templateService) findSection(ctx context.Context, templateID, sectionID uuid.UUID) (*domain.TemplateSection, error) {
+	sections, err := s.templateRepo.FindSectionsByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+	for i := range sections {
+		if sections[i].ID == sectionID {
+			return &sections[i], nil
+		}
+	}
+	return nil, NewAppError(http.StatusNotFound, "section not found")
+}
+
+// parseOrder parses the IDs of a reorder request, which must all be known
+// and listed once.
+func parseOrder(raw []string, known map[uuid.UUID]bool, noun string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(raw))
+	seen := make(map[uuid.UUID]bool, len(raw))
+	for _, r := range raw {
+		id, err := uuid.Parse(r)
+		if err != nil || !known[id] {
+			return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("unknown %s %s", noun, r))
+		}
+		if seen[id] {
+			return nil, NewAppError(http.StatusBadRequest, fmt.Sprintf("%s %s is listed twice", noun, r))
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
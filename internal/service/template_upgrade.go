@@ -0,0 +1,203 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+// GetTemplateUpgrade previews what UpgradeTemplate would change. The plan
+// is empty when the event already uses the latest template version.
+func (s *eventService) GetTemplateUpgrade(ctx context.Context, userID, eventID uuid.UUID) (*domain.TemplateUpgrade, error) {
+	event, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+	return s.planTemplateUpgrade(ctx, event)
+}
+
+// UpgradeTemplate brings the event's sections up to the latest version of
+// its template: new sections are added and fields the owner never edited
+// take the new defaults. Edited fields, and sections the template dropped,
+// are kept as they are.
+func (s *eventService) UpgradeTemplate(ctx context.Context, userID, eventID uuid.UUID) (*domain.TemplateUpgrade, error) {
+	event, err := s.authz.Authorize(ctx, userID, eventID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrade, err := s.planTemplateUpgrade(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	if upgrade.FromVersion == upgrade.ToVersion {
+		return upgrade, nil
+	}
+
+	applied, err := s.eventRepo.ApplyTemplateUpgrade(ctx, eventID, upgrade)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade template: %w", err)
+	}
+	if !applied {
+		return nil, NewAppError(http.StatusConflict, "event was changed in the meantime, please try again")
+	}
+	return upgrade, nil
+}
+
+func (s *eventService) planTemplateUpgrade(ctx context.Context, event *domain.Event) (*domain.TemplateUpgrade, error) {
+	tmpl, err := s.templateRepo.FindByID(ctx, event.TemplateID)
+	if err != nil || tmpl == nil {
+		return nil, NewAppError(http.StatusNotFound, "template not found")
+	}
+
+	upgrade := &domain.TemplateUpgrade{
+		FromVersion: event.TemplateVersion,
+		ToVersion:   event.TemplateVersion,
+		Added:       []domain.EventSection{},
+		Updated:     []domain.EventSection{},
+		Original:    map[uuid.UUID]json.RawMessage{},
+	}
+	if tmpl.Version <= event.TemplateVersion {
+		return upgrade, nil
+	}
+	upgrade.ToVersion = tmpl.Version
+
+	latest, err := s.templateRepo.FindSectionsByTemplateID(ctx, tmpl.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template sections: %w", err)
+	}
+	sections, err := s.eventRepo.FindSectionsByEventID(ctx, event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sections: %w", err)
+	}
+
+	// Defaults the event was created (or last upgraded) with tell edited
+	// fields apart from untouched ones. Without a snapshot only missing
+	// fields are filled in.
+	oldDefaults := make(map[uuid.UUID]json.RawMessage)
+	if v, err := s.templateRepo.FindVersion(ctx, tmpl.ID, event.TemplateVersion); err == nil {
+		var old []domain.TemplateSection
+		if err := json.Unmarshal(v.Sections, &old); err == nil {
+			for _, ts := range old {
+				oldDefaults[ts.ID] = ts.DefaultContent
+			}
+		}
+	}
+
+	byTemplateSection := make(map[uuid.UUID]*domain.EventSection, len(sections))
+	for i := range sections {
+		byTemplateSection[sections[i].TemplateSectionID] = &sections[i]
+	}
+
+	for _, ts := range latest {
+		section, ok := byTemplateSection[ts.ID]
+		if !ok {
+			content := ts.DefaultContent
+			if content == nil {
+				content = json.RawMessage(`{}`)
+			}
+			upgrade.Added = append(upgrade.Added, domain.EventSection{
+				ID:                uuid.New(),
+				EventID:           event.ID,
+				TemplateSectionID: ts.ID,
+				Type:              ts.Type,
+				Content:           content,
+				IsVisible:         true,
+				SortOrder:         ts.SortOrder,
+				VisibleGroups:     pq.StringArray{},
+				VisibleGuestIDs:   pq.StringArray{},
+			})
+			continue
+		}
+
+		if merged, changed := mergeSectionContent(oldDefaults[ts.ID], ts.DefaultContent, section.Content); changed {
+			upgrade.Original[section.ID] = section.Content
+			section.Content = merged
+			upgrade.Updated = append(upgrade.Updated, *section)
+		}
+	}
+	return upgrade, nil
+}
+
+// mergeSectionContent applies new template defaults to the content of an
+// event section and reports whether anything changed. Content that does not
+// decode as an object is left alone.
+func mergeSectionContent(oldDefault, newDefault, content json.RawMessage) (json.RawMessage, bool) {
+	current, ok := decodeContent(content)
+	if !ok {
+		return content, false
+	}
+	latest, _ := decodeContent(newDefault)
+	old, _ := decodeContent(oldDefault)
+
+	merged := mergeContent(old, latest, current)
+	if reflect.DeepEqual(merged, current) {
+		return content, false
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return content, false
+	}
+	return data, true
+}
+
+// mergeContent merges field by field. A field takes the new default when
+// the event lacks it or still holds the old default; nested objects merge
+// the same way. Fields the template dropped are removed unless edited, and
+// fields the owner removed stay removed.
+func mergeContent(old, latest, current map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(current))
+	for k, v := range current {
+		merged[k] = v
+	}
+
+	for k, nv := range latest {
+		cv, has := current[k]
+		ov, hadOld := old[k]
+		switch {
+		case !has:
+			if !hadOld {
+				merged[k] = nv
+			}
+		case hadOld && reflect.DeepEqual(cv, ov):
+			merged[k] = nv
+		default:
+			cm, cok := cv.(map[string]interface{})
+			nm, nok := nv.(map[string]interface{})
+			if cok && nok {
+				om, _ := ov.(map[string]interface{})
+				merged[k] = mergeContent(om, nm, cm)
+			}
+		}
+	}
+
+	for k, ov := range old {
+		if _, kept := latest[k]; kept {
+			continue
+		}
+		if cv, has := current[k]; has && reflect.DeepEqual(cv, ov) {
+			delete(merged, k)
+		}
+	}
+	return merged
+}
+
+func decodeContent(content json.RawMessage) (map[string]interface{}, bool) {
+	if len(content) == 0 {
+		return nil, false
+	}
+	var data map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil || data == nil {
+		return nil, false
+	}
+	return data, true
+}
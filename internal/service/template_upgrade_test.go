@@ -0,0 +1,211 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMergeContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		latest  string
+		current string
+		want    string
+	}{
+		{
+			name:    "untouched field takes new default",
+			old:     `{"title": "Undangan"}`,
+			latest:  `{"title": "Undangan Pernikahan"}`,
+			current: `{"title": "Undangan"}`,
+			want:    `{"title": "Undangan Pernikahan"}`,
+		},
+		{
+			name:    "edited field is kept",
+			old:     `{"title": "Undangan"}`,
+			latest:  `{"title": "Undangan Pernikahan"}`,
+			current: `{"title": "Rina & Dimas"}`,
+			want:    `{"title": "Rina & Dimas"}`,
+		},
+		{
+			name:    "missing field is added",
+			old:     `{"title": "Undangan"}`,
+			latest:  `{"title": "Undangan", "subtitle": "Kami mengundang"}`,
+			current: `{"title": "Undangan"}`,
+			want:    `{"title": "Undangan", "subtitle": "Kami mengundang"}`,
+		},
+		{
+			name:    "field deleted by owner stays deleted",
+			old:     `{"title": "Undangan", "subtitle": "Kami mengundang"}`,
+			latest:  `{"title": "Undangan", "subtitle": "Dengan hormat"}`,
+			current: `{"title": "Undangan"}`,
+			want:    `{"title": "Undangan"}`,
+		},
+		{
+			name:    "without a snapshot only missing fields are filled",
+			old:     `{}`,
+			latest:  `{"title": "Undangan Pernikahan", "subtitle": "Kami mengundang"}`,
+			current: `{"title": "Undangan"}`,
+			want:    `{"title": "Undangan", "subtitle": "Kami mengundang"}`,
+		},
+		{
+			name:    "untouched field dropped by template is removed",
+			old:     `{"title": "Undangan", "note": "Harap datang"}`,
+			latest:  `{"title": "Undangan"}`,
+			current: `{"title": "Undangan", "note": "Harap datang"}`,
+			want:    `{"title": "Undangan"}`,
+		},
+		{
+			name:    "edited field dropped by template is kept",
+			old:     `{"title": "Undangan", "note": "Harap datang"}`,
+			latest:  `{"title": "Undangan"}`,
+			current: `{"title": "Undangan", "note": "Datang jam 7"}`,
+			want:    `{"title": "Undangan", "note": "Datang jam 7"}`,
+		},
+		{
+			name:    "owner's own fields are kept",
+			old:     `{"title": "Undangan"}`,
+			latest:  `{"title": "Undangan"}`,
+			current: `{"title": "Undangan", "extra": "x"}`,
+			want:    `{"title": "Undangan", "extra": "x"}`,
+		},
+		{
+			name:    "nested object merges field by field",
+			old:     `{"bride": {"name": "Nama", "parents": "Putri dari"}}`,
+			latest:  `{"bride": {"name": "Nama Mempelai", "parents": "Putri dari Bapak", "instagram": "@"}}`,
+			current: `{"bride": {"name": "Rina", "parents": "Putri dari"}}`,
+			want:    `{"bride": {"name": "Rina", "parents": "Putri dari Bapak", "instagram": "@"}}`,
+		},
+		{
+			name:    "untouched nested object is replaced",
+			old:     `{"bride": {"name": "Nama"}}`,
+			latest:  `{"bride": {"name": "Nama Mempelai"}}`,
+			current: `{"bride": {"name": "Nama"}}`,
+			want:    `{"bride": {"name": "Nama Mempelai"}}`,
+		},
+		{
+			name:    "nested key deleted by owner stays deleted",
+			old:     `{"bride": {"name": "Nama", "instagram": "@"}}`,
+			latest:  `{"bride": {"name": "Nama", "instagram": "@akun"}}`,
+			current: `{"bride": {"name": "Rina"}}`,
+			want:    `{"bride": {"name": "Rina"}}`,
+		},
+		{
+			name:    "nested object new to the template",
+			old:     `{}`,
+			latest:  `{"bride": {"name": "Nama"}}`,
+			current: `{"bride": {"name": "Rina"}}`,
+			want:    `{"bride": {"name": "Rina"}}`,
+		},
+		{
+			name:    "edited field replaced by an object is kept",
+			old:     `{"bride": "Nama"}`,
+			latest:  `{"bride": {"name": "Nama"}}`,
+			current: `{"bride": "Rina"}`,
+			want:    `{"bride": "Rina"}`,
+		},
+		{
+			name:    "lists are replaced whole, not merged",
+			old:     `{"accounts": [{"bank": "BCA"}]}`,
+			latest:  `{"accounts": [{"bank": "BCA"}, {"bank": "BNI"}]}`,
+			current: `{"accounts": [{"bank": "Mandiri"}]}`,
+			want:    `{"accounts": [{"bank": "Mandiri"}]}`,
+		},
+		{
+			name:    "numbers compare exactly",
+			old:     `{"count": 1.0}`,
+			latest:  `{"count": 2}`,
+			current: `{"count": 1}`,
+			want:    `{"count": 1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeContent(decode(t, tt.old), decode(t, tt.latest), decode(t, tt.current))
+			if want := decode(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("mergeContent() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMergeSectionContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldDefault  string
+		newDefault  string
+		content     string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "changed",
+			oldDefault:  `{"title": "Undangan"}`,
+			newDefault:  `{"title": "Undangan Pernikahan"}`,
+			content:     `{"title": "Undangan"}`,
+			want:        `{"title":"Undangan Pernikahan"}`,
+			wantChanged: true,
+		},
+		{
+			name:       "nothing to change keeps the original bytes",
+			oldDefault: `{"title": "Undangan"}`,
+			newDefault: `{"title": "Undangan Pernikahan"}`,
+			content:    `{ "title" : "Rina & Dimas" }`,
+			want:       `{ "title" : "Rina & Dimas" }`,
+		},
+		{
+			name:        "missing old default",
+			newDefault:  `{"title": "Undangan"}`,
+			content:     `{}`,
+			want:        `{"title":"Undangan"}`,
+			wantChanged: true,
+		},
+		{
+			name:       "missing new default",
+			oldDefault: `{"title": "Undangan"}`,
+			content:    `{"title": "Rina"}`,
+			want:       `{"title": "Rina"}`,
+		},
+		{
+			name:       "content that is not an object is left alone",
+			oldDefault: `{}`,
+			newDefault: `{"title": "Undangan"}`,
+			content:    `["x"]`,
+			want:       `["x"]`,
+		},
+		{
+			name:       "empty content is left alone",
+			newDefault: `{"title": "Undangan"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := mergeSectionContent(raw(tt.oldDefault), raw(tt.newDefault), raw(tt.content))
+			if changed != tt.wantChanged {
+				t.Errorf("mergeSectionContent() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if string(got) != tt.want {
+				t.Errorf("mergeSectionContent() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func decode(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	data, ok := decodeContent(json.RawMessage(s))
+	if !ok {
+		t.Fatalf("invalid test content %s", s)
+	}
+	return data
+}
+
+func raw(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	return json.RawMessage(s)
+}
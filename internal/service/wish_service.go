@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/galihaleanda/event-invitation/internal/domain"
+)
+
+const (
+	defaultWishesLimit = 20
+	maxWishesLimit     = 100
+)
+
+type WishService interface {
+	GetPublic(ctx context.Context, slug string, page, limit int) (*domain.WishPage, error)
+	GetForOwner(ctx context.Context, userID, eventID uuid.UUID, status domain.MessageStatus) ([]domain.Guest, error)
+	Moderate(ctx context.Context, userID, eventID, guestID uuid.UUID, action string) (*domain.Guest, error)
+	Delete(ctx context.Context, userID, eventID, guestID uuid.UUID) error
+}
+
+type wishService struct {
+	guestRepo domain.GuestRepository
+	eventRepo domain.EventRepository
+	authz     Authorizer
+}
+
+func NewWishService(guestRepo domain.GuestRepository, eventRepo domain.EventRepository, authz Authorizer) WishService {
+	return &wishService{guestRepo: guestRepo, eventRepo: eventRepo, authz: authz}
+}
+
+// GetPublic returns approved wishes of a published event, pinned first.
+func (s *wishService) GetPublic(ctx context.Context, slug string, page, limit int) (*domain.WishPage, error) {
+	event, err := s.eventRepo.FindBySlug(ctx, slug)
+	if err != nil || event == nil || !event.IsPublished {
+		return nil, NewAppError(http.StatusNotFound, "event not found")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultWishesLimit
+	}
+	if limit > maxWishesLimit {
+		limit = maxWishesLimit
+	}
+
+	wishes, err := s.guestRepo.FindApprovedWishes(ctx, event.ID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishes: %w", err)
+	}
+	total, err := s.guestRepo.CountApprovedWishes(ctx, event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count wishes: %w", err)
+	}
+	if wishes == nil {
+		wishes = []domain.Wish{}
+	}
+
+	return &domain.WishPage{Items: wishes, Page: page, Limit: limit, Total: total}, nil
+}
+
+func (s *wishService) GetForOwner(ctx context.Context, userID, eventID uuid.UUID, status domain.MessageStatus) ([]domain.Guest, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionViewGuests); err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case "", domain.MessageStatusPending, domain.MessageStatusApproved, domain.MessageStatusHidden:
+	default:
+		return nil, NewAppError(http.StatusBadRequest, "invalid status")
+	}
+
+	guests, err := s.guestRepo.FindMessagesByEventID(ctx, eventID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishes: %w", err)
+	}
+	return guests, nil
+}
+
+func (s *wishService) Moderate(ctx context.Context, userID, eventID, guestID uuid.UUID, action string) (*domain.Guest, error) {
+	guest, err := s.findOwnedWish(ctx, userID, eventID, guestID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case "approve":
+		err = s.guestRepo.UpdateMessageStatus(ctx, guest.ID, domain.MessageStatusApproved)
+		guest.MessageStatus = domain.MessageStatusApproved
+	case "hide":
+		err = s.guestRepo.UpdateMessageStatus(ctx, guest.ID, domain.MessageStatusHidden)
+		guest.MessageStatus = domain.MessageStatusHidden
+	case "pin", "unpin":
+		err = s.guestRepo.UpdateMessagePinned(ctx, guest.ID, action == "pin")
+		guest.MessagePinned = action == "pin"
+	default:
+		return nil, NewAppError(http.StatusBadRequest, "invalid action")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate wish: %w", err)
+	}
+	return guest, nil
+}
+
+// Delete removes the wish only; the guest's RSVP stays.
+func (s *wishService) Delete(ctx context.Context, userID, eventID, guestID uuid.UUID) error {
+	guest, err := s.findOwnedWish(ctx, userID, eventID, guestID)
+	if err != nil {
+		return err
+	}
+	if err := s.guestRepo.ClearMessage(ctx, guest.ID); err != nil {
+		return fmt.Errorf("failed to delete wish: %w", err)
+	}
+	return nil
+}
+
+func (s *wishService) findOwnedWish(ctx context.Context, userID, eventID, guestID uuid.UUID) (*domain.Guest, error) {
+	if _, err := s.authz.Authorize(ctx, userID, eventID, ActionManageGuests); err != nil {
+		return nil, err
+	}
+
+	guest, err := s.guestRepo.FindByID(ctx, guestID)
+	if err != nil || guest.EventID != eventID || guest.Message == nil || *guest.Message == "" {
+		return nil, NewAppError(http.StatusNotFound, "wish not found")
+	}
+	return guest, nil
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+const guestCodeAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+
+// GenerateGuestCode returns an unguessable code used in personalized
+// invitation links (/e/:slug?to=<code>).
+func GenerateGuestCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate guest code: %w", err)
+	}
+	for i := range b {
+		b[i] = guestCodeAlphabet[b[i]&31]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// PDFColumn describes one column of a printable table; Width is in mm.
+type PDFColumn struct {
+	Title string
+	Width float64
+}
+
+// WriteTablePDF renders a landscape A4 table with a title block, repeating
+// the column header on every page.
+func WriteTablePDF(w io.Writer, title, subtitle string, columns []PDFColumn, rows [][]string) error {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 12)
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-10)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for _, col := range columns {
+			pdf.CellFormat(col.Width, 8, tr(col.Title), "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 9)
+	}
+	pdf.SetHeaderFunc(func() {
+		if pdf.PageNo() > 1 {
+			header()
+		}
+	})
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 8, tr(title), "", 1, "L", false, 0, "")
+	if subtitle != "" {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(subtitle), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(2)
+	header()
+
+	for _, row := range rows {
+		for i, col := range columns {
+			value := ""
+			if i < len(row) {
+				value = truncateToWidth(pdf, tr, row[i], col.Width-2)
+			}
+			pdf.CellFormat(col.Width, 8, value, "1", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("failed to write pdf: %w", err)
+	}
+	return nil
+}
+
+// truncateToWidth shortens s (before encoding with tr) so it fits in a cell.
+func truncateToWidth(pdf *fpdf.Fpdf, tr func(string) string, s string, width float64) string {
+	if pdf.GetStringWidth(tr(s)) <= width {
+		return tr(s)
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(tr(string(runes)+"...")) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return tr(string(runes) + "...")
+}
@@ -0,0 +1,19 @@
+package utils
+
+import "strings"
+
+// NormalizePhone reduces a phone number to digits with the Indonesian
+// country code, so "0812-3456", "+62 8123456" and "628123456" compare equal.
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if strings.HasPrefix(digits, "0") {
+		digits = "62" + digits[1:]
+	}
+	return digits
+}
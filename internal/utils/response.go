@@ -28,6 +28,16 @@ func RespondError(c *gin.Context, statusCode int, err string) {
 	})
 }
 
+// RespondErrorWithData is RespondError plus a payload describing the failure
+// (e.g. per-row validation errors).
+func RespondErrorWithData(c *gin.Context, statusCode int, err string, data interface{}) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Error:   err,
+		Data:    data,
+	})
+}
+
 func RespondCreated(c *gin.Context, data interface{}) {
 	RespondSuccess(c, http.StatusCreated, "created successfully", data)
 }
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/gosimple/slug"
@@ -16,6 +17,30 @@ func GenerateSlug(title string) string {
 	return fmt.Sprintf("%s-%s", base, randomSuffix(6))
 }
 
+// reservedSlugs are top-level paths served by routes other than the
+// invitation page (see cmd/api/main.go), plus a few kept for later use.
+var reservedSlugs = map[string]bool{
+	"api":         true,
+	"uploads":     true,
+	"health":      true,
+	"admin":       true,
+	"static":      true,
+	"assets":      true,
+	"login":       true,
+	"register":    true,
+	"e":           true,
+	"rsvp":        true,
+	"favicon.ico": true,
+	"robots.txt":  true,
+	"sitemap.xml": true,
+}
+
+// IsReservedSlug reports whether slug would shadow, or be shadowed by,
+// another top-level route.
+func IsReservedSlug(s string) bool {
+	return reservedSlugs[strings.ToLower(s)]
+}
+
 func randomSuffix(n int) string {
 	const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ReadSpreadsheet returns the rows of a CSV or XLSX file (first sheet),
+// picking the format from the file extension.
+func ReadSpreadsheet(filename string, r io.Reader) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+		return rows, nil
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open xlsx: %w", err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("xlsx has no sheets")
+		}
+		rows, err := f.GetRows(sheets[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read xlsx: %w", err)
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unsupported file type, use .csv or .xlsx")
+	}
+}
+
+// WriteCSV writes header and rows as CSV. Cells that a spreadsheet would
+// read as a formula are escaped, since values come from guests.
+func WriteCSV(w io.Writer, header []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(escapeFormulas(header)); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	for _, row := range rows {
+		if err := writer.Write(escapeFormulas(row)); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// escapeFormulas prefixes cells starting with =, +, -, @, tab or carriage
+// return with a quote so they open as text.
+func escapeFormulas(values []string) []string {
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			v = "'" + v
+		}
+		escaped[i] = v
+	}
+	return escaped
+}
+
+// XLSXSheet is one worksheet of a workbook written by WriteXLSX.
+type XLSXSheet struct {
+	Name   string
+	Header []string
+	Rows   [][]string
+}
+
+// WriteXLSX writes each sheet's header and rows to a workbook. Every value
+// is stored as a string cell, so nothing is evaluated as a formula.
+func WriteXLSX(w io.Writer, sheets ...XLSXSheet) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	for i, sheet := range sheets {
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", sheet.Name); err != nil {
+				return fmt.Errorf("failed to write xlsx: %w", err)
+			}
+		} else if _, err := f.NewSheet(sheet.Name); err != nil {
+			return fmt.Errorf("failed to write xlsx: %w", err)
+		}
+
+		writeRow := func(rowNum int, values []string) error {
+			for col, v := range values {
+				cell, err := excelize.CoordinatesToCellName(col+1, rowNum)
+				if err != nil {
+					return err
+				}
+				if err := f.SetCellStr(sheet.Name, cell, v); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if err := writeRow(1, sheet.Header); err != nil {
+			return fmt.Errorf("failed to write xlsx: %w", err)
+		}
+		for i, values := range sheet.Rows {
+			if err := writeRow(i+2, values); err != nil {
+				return fmt.Errorf("failed to write xlsx: %w", err)
+			}
+		}
+	}
+
+	if _, err := f.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write xlsx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+-- 0002_guest_invitations.down.sql
+DROP INDEX IF EXISTS idx_guests_guest_code;
+CREATE INDEX idx_guests_guest_code ON guests(guest_code);
+
+ALTER TABLE guests DROP COLUMN IF EXISTS party_size;
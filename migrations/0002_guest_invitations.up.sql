@@ -0,0 +1,17 @@
+-- 0002_guest_invitations.up.sql
+
+-- Pre-registered guests: invited seats per invitation, server-minted codes
+ALTER TABLE guests ADD COLUMN party_size INT NOT NULL DEFAULT 1;
+
+-- Existing codes were picked by the client, so they may repeat and are easy
+-- to guess. Replace them with random ones in the GenerateGuestCode alphabet,
+-- skipping the UUID's fixed version and variant bytes.
+UPDATE guests g SET guest_code = (
+    SELECT string_agg(substr('abcdefghijkmnpqrstuvwxyz23456789', (get_byte(r.b, i.n) & 31) + 1, 1), '' ORDER BY i.pos)
+    FROM (SELECT uuid_send(gen_random_uuid()) AS b WHERE g.id IS NOT NULL) r,
+        unnest(ARRAY[0, 1, 2, 3, 4, 5, 7, 9, 10, 11]) WITH ORDINALITY AS i(n, pos)
+)
+WHERE guest_code IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_guests_guest_code;
+CREATE UNIQUE INDEX idx_guests_guest_code ON guests(guest_code) WHERE guest_code IS NOT NULL;
@@ -0,0 +1,3 @@
+-- 0003_rsvp_party_size.down.sql
+ALTER TABLE events DROP COLUMN IF EXISTS max_party_size;
+ALTER TABLE guests DROP COLUMN IF EXISTS attendees;
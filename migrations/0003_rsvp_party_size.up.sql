@@ -0,0 +1,7 @@
+-- 0003_rsvp_party_size.up.sql
+
+-- Headcount per RSVP (0 unless attending) and default seats per invite
+ALTER TABLE guests ADD COLUMN attendees INT NOT NULL DEFAULT 0;
+UPDATE guests SET attendees = 1 WHERE rsvp_status = 'yes';
+
+ALTER TABLE events ADD COLUMN max_party_size INT NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- 0004_rsvp_limits.down.sql
+UPDATE guests SET rsvp_status = 'pending' WHERE rsvp_status = 'waitlist';
+
+ALTER TABLE events DROP COLUMN IF EXISTS waitlist_enabled;
+ALTER TABLE events DROP COLUMN IF EXISTS max_attendees;
+ALTER TABLE events DROP COLUMN IF EXISTS rsvp_deadline;
@@ -0,0 +1,6 @@
+-- 0004_rsvp_limits.up.sql
+
+-- RSVP window and headcount capacity per event
+ALTER TABLE events ADD COLUMN rsvp_deadline TIMESTAMP;
+ALTER TABLE events ADD COLUMN max_attendees INT;
+ALTER TABLE events ADD COLUMN waitlist_enabled BOOLEAN NOT NULL DEFAULT FALSE;
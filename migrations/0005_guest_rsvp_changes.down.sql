@@ -0,0 +1,2 @@
+-- 0005_guest_rsvp_changes.down.sql
+DROP TABLE IF EXISTS guest_rsvp_changes;
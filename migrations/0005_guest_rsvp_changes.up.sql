@@ -0,0 +1,14 @@
+-- 0005_guest_rsvp_changes.up.sql
+
+-- History of RSVP answer changes, shown to owners
+CREATE TABLE guest_rsvp_changes (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    guest_id       UUID NOT NULL REFERENCES guests(id) ON DELETE CASCADE,
+    from_status    VARCHAR(20) NOT NULL,
+    to_status      VARCHAR(20) NOT NULL,
+    from_attendees INT NOT NULL DEFAULT 0,
+    to_attendees   INT NOT NULL DEFAULT 0,
+    source         VARCHAR(20) NOT NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_guest_rsvp_changes_guest_id ON guest_rsvp_changes(guest_id);
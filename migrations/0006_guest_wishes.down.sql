@@ -0,0 +1,6 @@
+-- 0006_guest_wishes.down.sql
+ALTER TABLE events DROP COLUMN IF EXISTS wishes_auto_approve;
+
+DROP INDEX IF EXISTS idx_guests_event_id_message_status;
+ALTER TABLE guests DROP COLUMN IF EXISTS message_pinned;
+ALTER TABLE guests DROP COLUMN IF EXISTS message_status;
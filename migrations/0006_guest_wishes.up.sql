@@ -0,0 +1,9 @@
+-- 0006_guest_wishes.up.sql
+
+-- Moderation of guest messages shown on the public wishes wall
+ALTER TABLE guests ADD COLUMN message_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+ALTER TABLE guests ADD COLUMN message_pinned BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX idx_guests_event_id_message_status ON guests(event_id, message_status);
+
+-- Messages wait for owner review unless the owner opts in
+ALTER TABLE events ADD COLUMN wishes_auto_approve BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- 0007_event_questions.down.sql
+ALTER TABLE guests DROP COLUMN IF EXISTS answers;
+DROP TABLE IF EXISTS event_questions;
@@ -0,0 +1,17 @@
+-- 0007_event_questions.up.sql
+
+-- Custom RSVP form questions per event
+CREATE TABLE event_questions (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id    UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    label       VARCHAR(200) NOT NULL,
+    type        VARCHAR(20) NOT NULL,
+    options     TEXT[] NOT NULL DEFAULT '{}',
+    is_required BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order  INT NOT NULL DEFAULT 0,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_event_questions_event_id ON event_questions(event_id);
+
+-- Answers keyed by question id
+ALTER TABLE guests ADD COLUMN answers JSONB;
@@ -0,0 +1,3 @@
+-- 0008_event_sessions.down.sql
+ALTER TABLE guests DROP COLUMN IF EXISTS session_ids;
+DROP TABLE IF EXISTS event_sessions;
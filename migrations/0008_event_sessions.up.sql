@@ -0,0 +1,19 @@
+-- 0008_event_sessions.up.sql
+
+-- Sub-events within one invitation, e.g. akad and resepsi
+CREATE TABLE event_sessions (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id      UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name          VARCHAR(100) NOT NULL,
+    starts_at     TIMESTAMP NOT NULL,
+    ends_at       TIMESTAMP,
+    venue_name    VARCHAR(255),
+    venue_address TEXT,
+    map_url       TEXT,
+    sort_order    INT NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_event_sessions_event_id ON event_sessions(event_id);
+
+-- Sessions each guest will attend
+ALTER TABLE guests ADD COLUMN session_ids TEXT[] NOT NULL DEFAULT '{}';
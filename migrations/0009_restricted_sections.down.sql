@@ -0,0 +1,4 @@
+-- 0009_restricted_sections.down.sql
+ALTER TABLE event_sessions DROP COLUMN IF EXISTS visible_guest_ids, DROP COLUMN IF EXISTS visible_groups;
+ALTER TABLE event_sections DROP COLUMN IF EXISTS visible_guest_ids, DROP COLUMN IF EXISTS visible_groups;
+ALTER TABLE guests DROP COLUMN IF EXISTS guest_group;
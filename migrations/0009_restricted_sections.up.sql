@@ -0,0 +1,13 @@
+-- 0009_restricted_sections.up.sql
+
+-- Optional guest group, e.g. "keluarga" or "resepsi"
+ALTER TABLE guests ADD COLUMN guest_group VARCHAR(50);
+
+-- Audience of sections and sessions; both empty means everyone
+ALTER TABLE event_sections
+    ADD COLUMN visible_groups    TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN visible_guest_ids TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE event_sessions
+    ADD COLUMN visible_groups    TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN visible_guest_ids TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+-- 0010_guest_checkin.down.sql
+ALTER TABLE guests DROP COLUMN IF EXISTS checked_in_at;
@@ -0,0 +1,4 @@
+-- 0010_guest_checkin.up.sql
+
+-- Door check-in via QR code; NULL until the guest is scanned
+ALTER TABLE guests ADD COLUMN checked_in_at TIMESTAMP;
@@ -0,0 +1,2 @@
+-- 0011_event_collaborators.down.sql
+DROP TABLE IF EXISTS event_collaborators;
@@ -0,0 +1,17 @@
+-- 0011_event_collaborators.up.sql
+
+-- People who help run an event besides its owner (events.user_id).
+-- Invitations are addressed by email; user_id is set once accepted.
+CREATE TABLE event_collaborators (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id    UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    email       VARCHAR(255) NOT NULL,
+    user_id     UUID REFERENCES users(id) ON DELETE CASCADE,
+    role        VARCHAR(20) NOT NULL,
+    invited_by  UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    accepted_at TIMESTAMP,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (event_id, email)
+);
+CREATE INDEX idx_event_collaborators_user_id ON event_collaborators(user_id);
+CREATE INDEX idx_event_collaborators_email ON event_collaborators(email);
@@ -0,0 +1,2 @@
+-- 0012_media_storage_key.down.sql
+ALTER TABLE media DROP COLUMN IF EXISTS storage_key;
@@ -0,0 +1,7 @@
+-- 0012_media_storage_key.up.sql
+
+-- Key of the file in the storage backend, e.g. events/<event_id>/<file>
+ALTER TABLE media ADD COLUMN storage_key TEXT;
+
+-- Files uploaded before this migration live on local disk under events/
+UPDATE media SET storage_key = substring(file_url from '(events/.*)$') WHERE storage_key IS NULL;
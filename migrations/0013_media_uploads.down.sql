@@ -0,0 +1,2 @@
+-- 0013_media_uploads.down.sql
+DROP TABLE IF EXISTS media_uploads;
@@ -0,0 +1,17 @@
+-- 0013_media_uploads.up.sql
+
+-- Upload slots for direct-to-storage uploads; a media row is created once
+-- the client confirms and the object has been verified
+CREATE TABLE IF NOT EXISTS media_uploads (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id     UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    storage_key  TEXT NOT NULL,
+    media_type   VARCHAR(50) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes   BIGINT NOT NULL,
+    created_by   UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at   TIMESTAMP NOT NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_uploads_event_id ON media_uploads(event_id);
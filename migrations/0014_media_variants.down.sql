@@ -0,0 +1,7 @@
+-- 0014_media_variants.down.sql
+DROP INDEX IF EXISTS idx_media_pending;
+ALTER TABLE media
+    DROP COLUMN IF EXISTS processing_status,
+    DROP COLUMN IF EXISTS variants,
+    DROP COLUMN IF EXISTS height,
+    DROP COLUMN IF EXISTS width;
@@ -0,0 +1,14 @@
+-- 0014_media_variants.up.sql
+
+-- Image processing results: upright dimensions and resized JPEG variants
+ALTER TABLE media
+    ADD COLUMN width             INT,
+    ADD COLUMN height            INT,
+    ADD COLUMN variants          JSONB,
+    ADD COLUMN processing_status VARCHAR(20) NOT NULL DEFAULT 'ready';
+
+-- Existing photos are picked up by the processor on the next start. Photos
+-- linked from elsewhere have no storage key and cannot be processed.
+UPDATE media SET processing_status = 'pending' WHERE media_type = 'image' AND storage_key IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_media_pending ON media(created_at) WHERE processing_status = 'pending';
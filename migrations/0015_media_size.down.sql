@@ -0,0 +1,2 @@
+-- 0015_media_size.down.sql
+ALTER TABLE media DROP COLUMN IF EXISTS size_bytes;
@@ -0,0 +1,4 @@
+-- 0015_media_size.up.sql
+
+-- File size for per-event storage quotas; older rows count as 0
+ALTER TABLE media ADD COLUMN size_bytes BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- 0016_media_albums.down.sql
+DROP INDEX IF EXISTS idx_media_event_cover;
+ALTER TABLE media
+    DROP COLUMN IF EXISTS album_id,
+    DROP COLUMN IF EXISTS is_cover,
+    DROP COLUMN IF EXISTS sort_order,
+    DROP COLUMN IF EXISTS alt_text,
+    DROP COLUMN IF EXISTS caption;
+DROP TABLE IF EXISTS media_albums;
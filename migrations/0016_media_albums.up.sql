@@ -0,0 +1,27 @@
+-- 0016_media_albums.up.sql
+
+-- Optional albums to group an event's gallery, e.g. "Prewedding"
+CREATE TABLE IF NOT EXISTS media_albums (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id    UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    title       VARCHAR(100) NOT NULL,
+    sort_order  INT NOT NULL DEFAULT 0,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_albums_event_id ON media_albums(event_id);
+
+ALTER TABLE media
+    ADD COLUMN caption    TEXT,
+    ADD COLUMN alt_text   TEXT,
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0,
+    ADD COLUMN is_cover   BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN album_id   UUID REFERENCES media_albums(id) ON DELETE SET NULL;
+
+-- Keep the existing upload order
+UPDATE media m SET sort_order = o.rn
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY event_id ORDER BY created_at) - 1 AS rn FROM media) o
+WHERE m.id = o.id;
+
+-- At most one cover photo per event
+CREATE UNIQUE INDEX IF NOT EXISTS idx_media_event_cover ON media(event_id) WHERE is_cover;
@@ -0,0 +1,2 @@
+-- 0017_event_music.down.sql
+DROP TABLE IF EXISTS event_music;
@@ -0,0 +1,11 @@
+-- 0017_event_music.up.sql
+
+-- Background music of the invitation page: one audio media per event
+CREATE TABLE IF NOT EXISTS event_music (
+    event_id       UUID PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    media_id       UUID NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    autoplay       BOOLEAN NOT NULL DEFAULT TRUE,
+    loop           BOOLEAN NOT NULL DEFAULT TRUE,
+    start_seconds  INT NOT NULL DEFAULT 0,
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
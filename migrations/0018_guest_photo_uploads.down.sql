@@ -0,0 +1,7 @@
+-- 0018_guest_photo_uploads.down.sql
+DROP INDEX IF EXISTS idx_media_event_id_approval_status;
+ALTER TABLE media
+    DROP COLUMN IF EXISTS uploader_name,
+    DROP COLUMN IF EXISTS guest_id,
+    DROP COLUMN IF EXISTS approval_status;
+ALTER TABLE events DROP COLUMN IF EXISTS guest_uploads;
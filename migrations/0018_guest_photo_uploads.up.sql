@@ -0,0 +1,13 @@
+-- 0018_guest_photo_uploads.up.sql
+
+-- Who may upload photos from the public page: off, open (anyone) or
+-- guests (requires a guest code)
+ALTER TABLE events ADD COLUMN guest_uploads VARCHAR(20) NOT NULL DEFAULT 'off';
+
+-- Guest photos wait for owner approval before they appear in the gallery
+ALTER TABLE media
+    ADD COLUMN approval_status VARCHAR(20) NOT NULL DEFAULT 'approved',
+    ADD COLUMN guest_id        UUID REFERENCES guests(id) ON DELETE SET NULL,
+    ADD COLUMN uploader_name   VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_media_event_id_approval_status ON media(event_id, approval_status);
@@ -0,0 +1,6 @@
+-- 0019_template_admin.down.sql
+ALTER TABLE templates
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS thumbnail_key,
+    DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
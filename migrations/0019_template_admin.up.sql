@@ -0,0 +1,14 @@
+-- 0019_template_admin.up.sql
+
+-- Site-wide role; admins manage the template catalog
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user';
+
+ALTER TABLE templates
+    ADD COLUMN sort_order    INT NOT NULL DEFAULT 0,
+    ADD COLUMN thumbnail_key TEXT,
+    ADD COLUMN updated_at    TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- Keep the current listing order (newest first)
+UPDATE templates t SET sort_order = o.rn
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY created_at DESC) - 1 AS rn FROM templates) o
+WHERE t.id = o.id;
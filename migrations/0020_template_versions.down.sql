@@ -0,0 +1,4 @@
+-- 0020_template_versions.down.sql
+ALTER TABLE events DROP COLUMN IF EXISTS template_version;
+DROP TABLE IF EXISTS template_versions;
+ALTER TABLE templates DROP COLUMN IF EXISTS version;
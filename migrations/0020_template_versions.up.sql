@@ -0,0 +1,33 @@
+-- 0020_template_versions.up.sql
+
+-- Every change to a template's sections publishes a new version; the
+-- snapshot keeps each version's sections so event upgrades can tell which
+-- content owners edited.
+ALTER TABLE templates ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS template_versions (
+    template_id UUID NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+    version     INT NOT NULL,
+    sections    JSONB NOT NULL DEFAULT '[]',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (template_id, version)
+);
+
+-- Version of the template an event's sections were copied from
+ALTER TABLE events ADD COLUMN template_version INT NOT NULL DEFAULT 1;
+
+-- Current sections become version 1
+INSERT INTO template_versions (template_id, version, sections)
+SELECT t.id, 1, COALESCE((
+    SELECT jsonb_agg(jsonb_build_object(
+        'id', s.id,
+        'template_id', s.template_id,
+        'name', s.name,
+        'type', s.type,
+        'default_content', s.default_content,
+        'sort_order', s.sort_order
+    ) ORDER BY s.sort_order)
+    FROM template_sections s WHERE s.template_id = t.id
+), '[]'::jsonb)
+FROM templates t
+ON CONFLICT DO NOTHING;